@@ -1,13 +1,15 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/cloudchase/inference-runtime/engine"
+	"github.com/cloudchase/inference-runtime/registry"
+	"github.com/cloudchase/inference-runtime/templates"
 )
 
 // writeJSON writes a JSON response with the given status code.
@@ -24,37 +26,68 @@ func writeError(w http.ResponseWriter, status int, msg string) {
 	writeJSON(w, status, ErrorResponse{Error: msg})
 }
 
-// ensureModel resolves the model path and loads it if not already loaded.
-func (s *Server) ensureModel(model string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if s.engine.IsLoaded() && s.engine.ModelPath() == model {
-		return nil
+// requestContext derives a context for a single generation call from the
+// inbound HTTP request. It's always cancelled when the client disconnects
+// (r.Context() does this for us); if timeout parses to a positive duration,
+// the context is additionally bounded by that duration.
+func requestContext(r *http.Request, timeout string) (context.Context, context.CancelFunc) {
+	if timeout == "" {
+		return r.Context(), func() {}
 	}
-
-	modelPath, err := s.manager.ResolveModelPath(model)
-	if err != nil {
-		return fmt.Errorf("cannot resolve model: %w", err)
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d <= 0 {
+		return r.Context(), func() {}
 	}
+	return context.WithTimeout(r.Context(), d)
+}
 
-	// If a different model is already loaded, reset first.
-	if s.engine.IsLoaded() {
-		if err := s.engine.Reset(); err != nil {
-			return fmt.Errorf("reset failed: %w", err)
-		}
+// modelOptions returns engine.DefaultOptions(), overridden by model's saved
+// gallery config (if any), for use as the base buildOptions/buildOpenAIOptions
+// start from. A model with no saved config (the common case for models added
+// via `ir pull`/`ir run <path>`) just gets the engine's defaults.
+func (s *Server) modelOptions(model string) engine.GenerateOptions {
+	opts := engine.DefaultOptions()
+	cfg, err := s.manager.GetConfig(model)
+	if err != nil || cfg == nil {
+		return opts
 	}
-
-	if err := s.engine.LoadModel(modelPath); err != nil {
-		return fmt.Errorf("load model: %w", err)
+	if cfg.MaxTokens > 0 {
+		opts.MaxTokens = cfg.MaxTokens
+	}
+	if cfg.Temperature > 0 {
+		opts.Temperature = cfg.Temperature
+	}
+	if cfg.TopK > 0 {
+		opts.TopK = cfg.TopK
+	}
+	if cfg.TopP > 0 {
+		opts.TopP = cfg.TopP
+	}
+	if cfg.RepetitionPenalty > 0 {
+		opts.RepetitionPenalty = cfg.RepetitionPenalty
+	}
+	if len(cfg.Stop) > 0 {
+		opts.Stop = cfg.Stop
 	}
+	return opts
+}
 
-	return nil
+// reportKVCacheBytes updates the ir_kv_cache_bytes gauge for model from
+// eng's current KV cache size. Best-effort: when eng.KVCacheBytes returns
+// an error (e.g. no model loaded) the gauge keeps its last known value; a
+// backend that doesn't report the size sets it to 0.
+func (s *Server) reportKVCacheBytes(ctx context.Context, model string, eng *engine.Engine) {
+	if bytes, err := eng.KVCacheBytes(ctx); err == nil {
+		s.metrics.SetKVCacheBytes(model, bytes)
+	}
 }
 
-// buildOptions converts an API generate request into engine options.
-func buildOptions(req GenerateRequest) engine.GenerateOptions {
-	opts := engine.DefaultOptions()
+// buildOptions converts an API generate request into engine options,
+// starting from base (see modelOptions) rather than engine.DefaultOptions
+// so a gallery-installed model's saved sampler defaults still apply when
+// the request doesn't override them.
+func buildOptions(base engine.GenerateOptions, req GenerateRequest) engine.GenerateOptions {
+	opts := base
 	if req.MaxTokens > 0 {
 		opts.MaxTokens = req.MaxTokens
 	}
@@ -89,23 +122,38 @@ func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.ensureModel(req.Model); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+	opts := buildOptions(s.modelOptions(req.Model), req)
+
+	ctx, cancel := requestContext(r, req.RequestTimeout)
+	defer cancel()
+
+	lease, err := s.scheduler.Acquire(ctx, req.Model)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
 		return
 	}
-
-	opts := buildOptions(req)
+	defer lease.Release()
 
 	if req.Stream {
-		s.handleGenerateStream(w, r, req, opts)
+		s.handleGenerateStream(ctx, w, r, req, opts, lease.Engine())
 		return
 	}
 
-	output, err := s.engine.Generate(req.Prompt, opts)
+	start := time.Now()
+	output, err := lease.Engine().Generate(ctx, req.Prompt, opts)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	s.metrics.ObserveRequest("/api/generate", req.Model, status, time.Since(start))
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "generation failed: "+err.Error())
 		return
 	}
+	promptTokens, completionTokens := tokenCount(ctx, lease.Engine(), req.Prompt), tokenCount(ctx, lease.Engine(), output)
+	s.metrics.AddTokenCounts(req.Model, promptTokens, completionTokens)
+	s.reportKVCacheBytes(ctx, req.Model, lease.Engine())
+	logCompletion(ctx, "/api/generate", req.Model, promptTokens, completionTokens, time.Since(start), time.Since(start))
 
 	writeJSON(w, http.StatusOK, GenerateResponse{
 		Model:    req.Model,
@@ -115,7 +163,9 @@ func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
 }
 
 // handleGenerateStream handles streaming generation via SSE / JSON lines.
-func (s *Server) handleGenerateStream(w http.ResponseWriter, _ *http.Request, req GenerateRequest, opts engine.GenerateOptions) {
+// It stops emitting tokens as soon as ctx is done, whether that's because
+// the client disconnected or because req.RequestTimeout elapsed.
+func (s *Server) handleGenerateStream(ctx context.Context, w http.ResponseWriter, _ *http.Request, req GenerateRequest, opts engine.GenerateOptions, eng *engine.Engine) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		writeError(w, http.StatusInternalServerError, "streaming not supported")
@@ -129,8 +179,10 @@ func (s *Server) handleGenerateStream(w http.ResponseWriter, _ *http.Request, re
 	flusher.Flush()
 
 	encoder := json.NewEncoder(w)
+	timer := newTokenTimer()
 
-	err := s.engine.GenerateStream(req.Prompt, opts, func(token string) bool {
+	err := eng.GenerateStream(ctx, req.Prompt, opts, func(token string) bool {
+		timer.Tick()
 		resp := GenerateResponse{
 			Model:    req.Model,
 			Response: token,
@@ -144,9 +196,17 @@ func (s *Server) handleGenerateStream(w http.ResponseWriter, _ *http.Request, re
 		return true
 	})
 
+	status := "ok"
 	if err != nil {
+		status = "error"
 		log.Printf("streaming generation error: %v", err)
 	}
+	promptTokens := tokenCount(ctx, eng, req.Prompt)
+	s.metrics.ObserveRequest("/api/generate", req.Model, status, time.Since(timer.start))
+	s.metrics.AddTokenCounts(req.Model, promptTokens, timer.count)
+	timer.Observe(s.metrics, req.Model, promptTokens)
+	s.reportKVCacheBytes(ctx, req.Model, eng)
+	logCompletion(ctx, "/api/generate", req.Model, promptTokens, timer.count, timer.FirstTokenLatency(), timer.TotalLatency())
 
 	// Send final done message.
 	_ = encoder.Encode(GenerateResponse{
@@ -175,43 +235,66 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.ensureModel(req.Model); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
-		return
+	manifest, err := s.manager.GetModel(req.Model)
+	if err != nil {
+		manifest = &registry.ModelManifest{}
 	}
+	cfg, _ := s.manager.GetConfig(req.Model) // best-effort; nil means no gallery config
 
-	// Build a simple prompt from messages.
-	var sb strings.Builder
-	for _, msg := range req.Messages {
-		switch msg.Role {
-		case "system":
-			sb.WriteString("System: ")
-		case "user":
-			sb.WriteString("User: ")
-		case "assistant":
-			sb.WriteString("Assistant: ")
-		default:
-			sb.WriteString(msg.Role + ": ")
-		}
-		sb.WriteString(msg.Content)
-		sb.WriteString("\n")
+	msgs := make([]templates.Message, 0, len(req.Messages)+1)
+	if cfg != nil && cfg.System != "" && (len(req.Messages) == 0 || req.Messages[0].Role != "system") {
+		msgs = append(msgs, templates.Message{Role: "system", Content: cfg.System})
+	}
+	for _, m := range req.Messages {
+		msgs = append(msgs, templates.Message{Role: m.Role, Content: m.Content})
+	}
+	tools := make([]templates.Tool, len(req.Tools))
+	for i, t := range req.Tools {
+		tools[i] = templates.Tool{Name: t.Function.Name, Description: t.Function.Description, Parameters: t.Function.Parameters}
 	}
-	sb.WriteString("Assistant: ")
-	prompt := sb.String()
 
-	opts := engine.DefaultOptions()
+	prompt, err := templates.Render(manifest, msgs, tools, req.Template)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "render chat template: "+err.Error())
+		return
+	}
+
+	opts := s.modelOptions(req.Model)
 	opts.Stream = req.Stream
+	if len(manifest.StopSequences) > 0 {
+		opts.Stop = manifest.StopSequences
+	}
+
+	ctx, cancel := requestContext(r, req.RequestTimeout)
+	defer cancel()
+
+	lease, err := s.scheduler.Acquire(ctx, req.Model)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	defer lease.Release()
 
 	if req.Stream {
-		s.handleChatStream(w, r, req, prompt, opts)
+		s.handleChatStream(ctx, w, r, req, prompt, opts, lease.Engine())
 		return
 	}
 
-	output, err := s.engine.Generate(prompt, opts)
+	start := time.Now()
+	output, err := lease.Engine().Generate(ctx, prompt, opts)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	s.metrics.ObserveRequest("/api/chat", req.Model, status, time.Since(start))
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "generation failed: "+err.Error())
 		return
 	}
+	promptTokens, completionTokens := tokenCount(ctx, lease.Engine(), prompt), tokenCount(ctx, lease.Engine(), output)
+	s.metrics.AddTokenCounts(req.Model, promptTokens, completionTokens)
+	s.reportKVCacheBytes(ctx, req.Model, lease.Engine())
+	logCompletion(ctx, "/api/chat", req.Model, promptTokens, completionTokens, time.Since(start), time.Since(start))
 
 	writeJSON(w, http.StatusOK, ChatResponse{
 		Model: req.Model,
@@ -223,8 +306,9 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleChatStream handles streaming chat generation.
-func (s *Server) handleChatStream(w http.ResponseWriter, _ *http.Request, req ChatRequest, prompt string, opts engine.GenerateOptions) {
+// handleChatStream handles streaming chat generation. Generation stops as
+// soon as ctx is done; see handleGenerateStream.
+func (s *Server) handleChatStream(ctx context.Context, w http.ResponseWriter, _ *http.Request, req ChatRequest, prompt string, opts engine.GenerateOptions, eng *engine.Engine) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		writeError(w, http.StatusInternalServerError, "streaming not supported")
@@ -238,8 +322,10 @@ func (s *Server) handleChatStream(w http.ResponseWriter, _ *http.Request, req Ch
 	flusher.Flush()
 
 	encoder := json.NewEncoder(w)
+	timer := newTokenTimer()
 
-	err := s.engine.GenerateStream(prompt, opts, func(token string) bool {
+	err := eng.GenerateStream(ctx, prompt, opts, func(token string) bool {
+		timer.Tick()
 		resp := ChatResponse{
 			Model: req.Model,
 			Message: ChatMessage{
@@ -256,9 +342,17 @@ func (s *Server) handleChatStream(w http.ResponseWriter, _ *http.Request, req Ch
 		return true
 	})
 
+	status := "ok"
 	if err != nil {
+		status = "error"
 		log.Printf("streaming chat error: %v", err)
 	}
+	promptTokens := tokenCount(ctx, eng, prompt)
+	s.metrics.ObserveRequest("/api/chat", req.Model, status, time.Since(timer.start))
+	s.metrics.AddTokenCounts(req.Model, promptTokens, timer.count)
+	timer.Observe(s.metrics, req.Model, promptTokens)
+	s.reportKVCacheBytes(ctx, req.Model, eng)
+	logCompletion(ctx, "/api/chat", req.Model, promptTokens, timer.count, timer.FirstTokenLatency(), timer.TotalLatency())
 
 	// Send final done message.
 	_ = encoder.Encode(ChatResponse{
@@ -316,12 +410,19 @@ func (s *Server) handleDeleteModel(w http.ResponseWriter, r *http.Request) {
 
 // handleHealth handles GET /api/health.
 func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
-	resp := map[string]any{
-		"status":       "ok",
-		"model_loaded": s.engine.IsLoaded(),
-	}
-	if s.engine.IsLoaded() {
-		resp["model"] = s.engine.ModelPath()
-	}
-	writeJSON(w, http.StatusOK, resp)
+	loaded := s.scheduler.LoadedModels()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":        "ok",
+		"models_loaded": loaded,
+	})
+}
+
+// handlePS handles GET /api/ps, reporting currently-loaded models and
+// scheduler queue depth so operators can see admission pressure before it
+// shows up as request latency.
+func (s *Server) handlePS(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, PSResponse{
+		Models:     s.scheduler.LoadedModels(),
+		QueueDepth: s.scheduler.QueueDepth(),
+	})
 }
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"time"
+
+	"github.com/cloudchase/inference-runtime/metrics"
+)
+
+// tokenTimer measures per-phase throughput from stream callback timestamps:
+// time-to-first-token approximates prefill speed, and the spacing of
+// subsequent tokens approximates decode speed.
+type tokenTimer struct {
+	start      time.Time
+	firstToken time.Time
+	count      int
+}
+
+func newTokenTimer() *tokenTimer {
+	return &tokenTimer{start: time.Now()}
+}
+
+// Tick records the arrival of one generated token.
+func (t *tokenTimer) Tick() {
+	if t.count == 0 {
+		t.firstToken = time.Now()
+	}
+	t.count++
+}
+
+// Observe reports prefill/decode tokens-per-second samples (when there's
+// enough data to make them meaningful) to reg, feeding both the per-phase
+// histogram and the decode-phase EWMA gauge.
+func (t *tokenTimer) Observe(reg *metrics.Registry, model string, promptTokens int) {
+	if reg == nil || t.count == 0 {
+		return
+	}
+	if prefill := t.firstToken.Sub(t.start); prefill > 0 && promptTokens > 0 {
+		reg.ObservePhaseTokensPerSecond(model, metrics.PhasePrefill, float64(promptTokens)/prefill.Seconds())
+	}
+	if t.count > 1 {
+		if decode := time.Since(t.firstToken); decode > 0 {
+			rate := float64(t.count-1) / decode.Seconds()
+			reg.ObservePhaseTokensPerSecond(model, metrics.PhaseDecode, rate)
+			reg.ObserveTokenRate(model, rate)
+		}
+	}
+}
+
+// FirstTokenLatency returns the time from the timer's creation
+// (newTokenTimer) to the first Tick, or 0 if no token has arrived yet. Used for structured
+// completion logging.
+func (t *tokenTimer) FirstTokenLatency() time.Duration {
+	if t.count == 0 {
+		return 0
+	}
+	return t.firstToken.Sub(t.start)
+}
+
+// TotalLatency returns the time elapsed since the timer was created.
+func (t *tokenTimer) TotalLatency() time.Duration {
+	return time.Since(t.start)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// requestIDKey is the context key LoggingMiddleware stashes a request's id
+// under, so handlers deep in the call stack can tag their own structured
+// log lines with it without threading it through every function signature.
+type requestIDKey struct{}
+
+// newRequestID generates a short id for correlating one request's log
+// lines, independent of completionID (which is an OpenAI-facing response
+// field, not a log-correlation id).
+func newRequestID() string {
+	var buf [8]byte
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
+
+// requestIDFromContext returns the id LoggingMiddleware stashed in ctx, or
+// "" if ctx didn't come from a request LoggingMiddleware wrapped.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// statusRecorder wraps a ResponseWriter to capture the status code a
+// handler wrote, since http.ResponseWriter doesn't expose it afterward.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush forwards to the wrapped ResponseWriter when it supports flushing,
+// so streaming handlers behind LoggingMiddleware still see an http.Flusher.
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// LoggingMiddleware emits one structured zerolog JSON line per HTTP
+// request (method, path, status, latency, request id) and stashes that
+// request id in the request's context so handlers can tag their own
+// richer completion logs (model, token counts, first-token latency) with
+// the same id, without this generic wrapper needing to know about any of
+// that.
+func LoggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := newRequestID()
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		start := time.Now()
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		log.Info().
+			Str("request_id", id).
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Int("status", rec.status).
+			Dur("latency", time.Since(start)).
+			Msg("http request")
+	})
+}
+
+// logCompletion emits a structured zerolog line for one generation
+// request's outcome: token counts and the latency breakdown a Prometheus
+// histogram can't carry (this specific request's first-token delay).
+func logCompletion(ctx context.Context, endpoint, model string, promptTokens, completionTokens int, firstToken, total time.Duration) {
+	log.Info().
+		Str("request_id", requestIDFromContext(ctx)).
+		Str("endpoint", endpoint).
+		Str("model", model).
+		Int("prompt_tokens", promptTokens).
+		Int("completion_tokens", completionTokens).
+		Dur("first_token_latency", firstToken).
+		Dur("total_latency", total).
+		Msg("completion")
+}
@@ -0,0 +1,433 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudchase/inference-runtime/engine"
+	"github.com/cloudchase/inference-runtime/registry"
+	"github.com/cloudchase/inference-runtime/templates"
+)
+
+// completionID generates an id in the same shape OpenAI uses
+// ("chatcmpl-<hex>" / "cmpl-<hex>").
+func completionID(prefix string) string {
+	var buf [12]byte
+	_, _ = rand.Read(buf[:])
+	return prefix + "-" + hex.EncodeToString(buf[:])
+}
+
+// estimateTokens is a rough word-based stand-in for a real tokenizer, used
+// when tokenCount's real count isn't available (e.g. the backend doesn't
+// support Tokenize yet).
+func estimateTokens(s string) int {
+	return len(strings.Fields(s))
+}
+
+// tokenCount returns s's real token count via eng's tokenizer, falling back
+// to estimateTokens if the backend can't tokenize (no model loaded, or a
+// backend that doesn't implement Tokenize).
+func tokenCount(ctx context.Context, eng *engine.Engine, s string) int {
+	if n, err := eng.CountTokens(ctx, s); err == nil {
+		return n
+	}
+	return estimateTokens(s)
+}
+
+// buildOpenAIOptions converts an OpenAI chat/completion request into engine
+// generation options, starting from base (see Server.modelOptions) so a
+// gallery-installed model's saved sampler defaults still apply when the
+// request doesn't override them.
+func buildOpenAIOptions(base engine.GenerateOptions, maxTokens int, temperature, topP float64, stop StopSequences, stream bool) engine.GenerateOptions {
+	opts := base
+	if maxTokens > 0 {
+		opts.MaxTokens = maxTokens
+	}
+	if temperature > 0 {
+		opts.Temperature = temperature
+	}
+	if topP > 0 {
+		opts.TopP = topP
+	}
+	if len(stop) > 0 {
+		opts.Stop = []string(stop)
+	}
+	opts.Stream = stream
+	return opts
+}
+
+// handleOpenAIChat handles POST /v1/chat/completions.
+func (s *Server) handleOpenAIChat(w http.ResponseWriter, r *http.Request) {
+	var req OpenAIChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	if req.Model == "" {
+		writeError(w, http.StatusBadRequest, "model is required")
+		return
+	}
+	if len(req.Messages) == 0 {
+		writeError(w, http.StatusBadRequest, "messages are required")
+		return
+	}
+
+	manifest, err := s.manager.GetModel(req.Model)
+	if err != nil {
+		manifest = &registry.ModelManifest{}
+	}
+	cfg, _ := s.manager.GetConfig(req.Model) // best-effort; nil means no gallery config
+
+	msgs := make([]templates.Message, 0, len(req.Messages)+1)
+	if cfg != nil && cfg.System != "" && (len(req.Messages) == 0 || req.Messages[0].Role != "system") {
+		msgs = append(msgs, templates.Message{Role: "system", Content: cfg.System})
+	}
+	for _, m := range req.Messages {
+		msgs = append(msgs, templates.Message{Role: m.Role, Content: m.Content})
+	}
+	tools := make([]templates.Tool, len(req.Tools))
+	for i, t := range req.Tools {
+		tools[i] = templates.Tool{Name: t.Function.Name, Description: t.Function.Description, Parameters: t.Function.Parameters}
+	}
+
+	prompt, err := templates.Render(manifest, msgs, tools, "")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "render chat template: "+err.Error())
+		return
+	}
+
+	opts := buildOpenAIOptions(s.modelOptions(req.Model), req.MaxTokens, req.Temperature, req.TopP, req.Stop, req.Stream)
+	if len(req.Stop) == 0 && len(manifest.StopSequences) > 0 {
+		opts.Stop = manifest.StopSequences
+	}
+	ctx, cancel := requestContext(r, "")
+	defer cancel()
+
+	lease, err := s.scheduler.Acquire(ctx, req.Model)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	defer lease.Release()
+
+	id := completionID("chatcmpl")
+	created := time.Now().Unix()
+
+	if req.Stream {
+		s.streamOpenAIChat(ctx, w, req, prompt, opts, id, created, lease.Engine())
+		return
+	}
+
+	start := time.Now()
+	output, err := lease.Engine().Generate(ctx, prompt, opts)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	s.metrics.ObserveRequest("/v1/chat/completions", req.Model, status, time.Since(start))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "generation failed: "+err.Error())
+		return
+	}
+	promptTokens := tokenCount(ctx, lease.Engine(), prompt)
+	completionTokens := tokenCount(ctx, lease.Engine(), output)
+	s.metrics.AddTokenCounts(req.Model, promptTokens, completionTokens)
+	s.reportKVCacheBytes(ctx, req.Model, lease.Engine())
+	logCompletion(ctx, "/v1/chat/completions", req.Model, promptTokens, completionTokens, time.Since(start), time.Since(start))
+
+	writeJSON(w, http.StatusOK, OpenAIChatResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: created,
+		Model:   req.Model,
+		Choices: []OpenAIChatChoice{{
+			Index:        0,
+			Message:      ChatMessage{Role: "assistant", Content: output},
+			FinishReason: "stop",
+		}},
+		Usage: OpenAIUsage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		},
+	})
+}
+
+// streamOpenAIChat streams POST /v1/chat/completions as SSE `data: {...}`
+// frames terminated by `data: [DONE]`, per the OpenAI chat streaming format.
+func (s *Server) streamOpenAIChat(ctx context.Context, w http.ResponseWriter, req OpenAIChatRequest, prompt string, opts engine.GenerateOptions, id string, created int64, eng *engine.Engine) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	writeChunk := func(chunk OpenAIChatChunk) {
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			log.Printf("openai chat chunk encode error: %v", err)
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	first := true
+	timer := newTokenTimer()
+	err := eng.GenerateStream(ctx, prompt, opts, func(token string) bool {
+		timer.Tick()
+		delta := OpenAIChatDelta{Content: token}
+		if first {
+			delta.Role = "assistant"
+			first = false
+		}
+		writeChunk(OpenAIChatChunk{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   req.Model,
+			Choices: []OpenAIChatChunkChoice{{Index: 0, Delta: delta}},
+		})
+		return true
+	})
+	status := "ok"
+	if err != nil {
+		status = "error"
+		log.Printf("openai chat stream error: %v", err)
+	}
+	promptTokens := tokenCount(ctx, eng, prompt)
+	s.metrics.ObserveRequest("/v1/chat/completions", req.Model, status, time.Since(timer.start))
+	s.metrics.AddTokenCounts(req.Model, promptTokens, timer.count)
+	timer.Observe(s.metrics, req.Model, promptTokens)
+	s.reportKVCacheBytes(ctx, req.Model, eng)
+	logCompletion(ctx, "/v1/chat/completions", req.Model, promptTokens, timer.count, timer.FirstTokenLatency(), timer.TotalLatency())
+
+	finish := "stop"
+	writeChunk(OpenAIChatChunk{
+		ID:      id,
+		Object:  "chat.completion.chunk",
+		Created: created,
+		Model:   req.Model,
+		Choices: []OpenAIChatChunkChoice{{Index: 0, Delta: OpenAIChatDelta{}, FinishReason: &finish}},
+	})
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+// handleOpenAICompletions handles POST /v1/completions.
+func (s *Server) handleOpenAICompletions(w http.ResponseWriter, r *http.Request) {
+	var req OpenAICompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	if req.Model == "" {
+		writeError(w, http.StatusBadRequest, "model is required")
+		return
+	}
+	if req.Prompt == "" {
+		writeError(w, http.StatusBadRequest, "prompt is required")
+		return
+	}
+
+	opts := buildOpenAIOptions(s.modelOptions(req.Model), req.MaxTokens, req.Temperature, req.TopP, req.Stop, req.Stream)
+	ctx, cancel := requestContext(r, "")
+	defer cancel()
+
+	lease, err := s.scheduler.Acquire(ctx, req.Model)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	defer lease.Release()
+
+	id := completionID("cmpl")
+	created := time.Now().Unix()
+
+	if req.Stream {
+		s.streamOpenAICompletion(ctx, w, req, opts, id, created, lease.Engine())
+		return
+	}
+
+	start := time.Now()
+	output, err := lease.Engine().Generate(ctx, req.Prompt, opts)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	s.metrics.ObserveRequest("/v1/completions", req.Model, status, time.Since(start))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "generation failed: "+err.Error())
+		return
+	}
+	promptTokens := tokenCount(ctx, lease.Engine(), req.Prompt)
+	completionTokens := tokenCount(ctx, lease.Engine(), output)
+	s.metrics.AddTokenCounts(req.Model, promptTokens, completionTokens)
+	s.reportKVCacheBytes(ctx, req.Model, lease.Engine())
+	logCompletion(ctx, "/v1/completions", req.Model, promptTokens, completionTokens, time.Since(start), time.Since(start))
+
+	writeJSON(w, http.StatusOK, OpenAICompletionResponse{
+		ID:      id,
+		Object:  "text_completion",
+		Created: created,
+		Model:   req.Model,
+		Choices: []OpenAICompletionChoice{{
+			Index:        0,
+			Text:         output,
+			FinishReason: "stop",
+		}},
+		Usage: OpenAIUsage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		},
+	})
+}
+
+// streamOpenAICompletion streams POST /v1/completions as SSE `data: {...}`
+// frames terminated by `data: [DONE]`.
+func (s *Server) streamOpenAICompletion(ctx context.Context, w http.ResponseWriter, req OpenAICompletionRequest, opts engine.GenerateOptions, id string, created int64, eng *engine.Engine) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	writeChunk := func(chunk OpenAICompletionChunk) {
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			log.Printf("openai completion chunk encode error: %v", err)
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	timer := newTokenTimer()
+	err := eng.GenerateStream(ctx, req.Prompt, opts, func(token string) bool {
+		timer.Tick()
+		writeChunk(OpenAICompletionChunk{
+			ID:      id,
+			Object:  "text_completion",
+			Created: created,
+			Model:   req.Model,
+			Choices: []OpenAICompletionChoice{{Index: 0, Text: token}},
+		})
+		return true
+	})
+	status := "ok"
+	if err != nil {
+		status = "error"
+		log.Printf("openai completion stream error: %v", err)
+	}
+	promptTokens := tokenCount(ctx, eng, req.Prompt)
+	s.metrics.ObserveRequest("/v1/completions", req.Model, status, time.Since(timer.start))
+	s.metrics.AddTokenCounts(req.Model, promptTokens, timer.count)
+	timer.Observe(s.metrics, req.Model, promptTokens)
+	s.reportKVCacheBytes(ctx, req.Model, eng)
+	logCompletion(ctx, "/v1/completions", req.Model, promptTokens, timer.count, timer.FirstTokenLatency(), timer.TotalLatency())
+
+	writeChunk(OpenAICompletionChunk{
+		ID:      id,
+		Object:  "text_completion",
+		Created: created,
+		Model:   req.Model,
+		Choices: []OpenAICompletionChoice{{Index: 0, Text: "", FinishReason: "stop"}},
+	})
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+// handleOpenAIEmbeddings handles POST /v1/embeddings.
+func (s *Server) handleOpenAIEmbeddings(w http.ResponseWriter, r *http.Request) {
+	var req OpenAIEmbeddingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	if req.Model == "" {
+		writeError(w, http.StatusBadRequest, "model is required")
+		return
+	}
+	if len(req.Input) == 0 {
+		writeError(w, http.StatusBadRequest, "input is required")
+		return
+	}
+
+	ctx, cancel := requestContext(r, "")
+	defer cancel()
+
+	lease, err := s.scheduler.Acquire(ctx, req.Model)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	defer lease.Release()
+
+	start := time.Now()
+	data := make([]OpenAIEmbeddingData, len(req.Input))
+	promptTokens := 0
+	for i, text := range req.Input {
+		vec, err := lease.Engine().Embed(ctx, text)
+		if err != nil {
+			s.metrics.ObserveRequest("/v1/embeddings", req.Model, "error", time.Since(start))
+			writeError(w, http.StatusInternalServerError, "embedding failed: "+err.Error())
+			return
+		}
+		data[i] = OpenAIEmbeddingData{Object: "embedding", Index: i, Embedding: vec}
+		promptTokens += tokenCount(ctx, lease.Engine(), text)
+	}
+	s.metrics.ObserveRequest("/v1/embeddings", req.Model, "ok", time.Since(start))
+	s.metrics.AddTokenCounts(req.Model, promptTokens, 0)
+	logCompletion(ctx, "/v1/embeddings", req.Model, promptTokens, 0, time.Since(start), time.Since(start))
+
+	writeJSON(w, http.StatusOK, OpenAIEmbeddingResponse{
+		Object: "list",
+		Model:  req.Model,
+		Data:   data,
+		Usage:  OpenAIEmbeddingUsage{PromptTokens: promptTokens, TotalTokens: promptTokens},
+	})
+}
+
+// handleOpenAIModels handles GET /v1/models.
+func (s *Server) handleOpenAIModels(w http.ResponseWriter, _ *http.Request) {
+	manifests, err := s.manager.ListModels()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list models: "+err.Error())
+		return
+	}
+
+	data := make([]OpenAIModel, 0, len(manifests))
+	for _, m := range manifests {
+		data = append(data, OpenAIModel{
+			ID:      m.Name,
+			Object:  "model",
+			Created: m.AddedAt.Unix(),
+			OwnedBy: "inference-runtime",
+		})
+	}
+
+	writeJSON(w, http.StatusOK, OpenAIModelsResponse{Object: "list", Data: data})
+}
@@ -0,0 +1,233 @@
+package api
+
+import "encoding/json"
+
+// StopSequences accepts either a single string or an array of strings for
+// the OpenAI-style "stop" field and normalizes it to a slice.
+type StopSequences []string
+
+// UnmarshalJSON implements json.Unmarshaler, accepting both `"stop"` and
+// `["a", "b"]` shapes.
+func (s *StopSequences) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		if single != "" {
+			*s = StopSequences{single}
+		}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*s = multi
+	return nil
+}
+
+// OpenAIChatRequest is the JSON body for POST /v1/chat/completions.
+type OpenAIChatRequest struct {
+	Model            string             `json:"model"`
+	Messages         []ChatMessage      `json:"messages"`
+	MaxTokens        int                `json:"max_tokens,omitempty"`
+	Temperature      float64            `json:"temperature,omitempty"`
+	TopP             float64            `json:"top_p,omitempty"`
+	Stop             StopSequences      `json:"stop,omitempty"`
+	Stream           bool               `json:"stream,omitempty"`
+	N                int                `json:"n,omitempty"`
+	PresencePenalty  float64            `json:"presence_penalty,omitempty"`
+	FrequencyPenalty float64            `json:"frequency_penalty,omitempty"`
+	LogitBias        map[string]float64 `json:"logit_bias,omitempty"`
+	User             string             `json:"user,omitempty"`
+	// Tools and ToolChoice mirror OpenAI's function-calling request schema.
+	// Tools is passed through to chat-template rendering; ToolChoice is
+	// accepted but not read. No chat template in this runtime parses tool
+	// calls back out of model output, so Choices[].Message.ToolCalls is
+	// always empty.
+	Tools      []ToolDefinition `json:"tools,omitempty"`
+	ToolChoice string           `json:"tool_choice,omitempty"`
+	// Logprobs and TopLogprobs mirror OpenAI's request schema. Neither is
+	// implemented yet: requesting them doesn't error, but
+	// Choices[].Logprobs is always null in the response.
+	Logprobs    bool `json:"logprobs,omitempty"`
+	TopLogprobs int  `json:"top_logprobs,omitempty"`
+}
+
+// OpenAICompletionRequest is the JSON body for POST /v1/completions.
+type OpenAICompletionRequest struct {
+	Model            string             `json:"model"`
+	Prompt           string             `json:"prompt"`
+	MaxTokens        int                `json:"max_tokens,omitempty"`
+	Temperature      float64            `json:"temperature,omitempty"`
+	TopP             float64            `json:"top_p,omitempty"`
+	Stop             StopSequences      `json:"stop,omitempty"`
+	Stream           bool               `json:"stream,omitempty"`
+	N                int                `json:"n,omitempty"`
+	PresencePenalty  float64            `json:"presence_penalty,omitempty"`
+	FrequencyPenalty float64            `json:"frequency_penalty,omitempty"`
+	LogitBias        map[string]float64 `json:"logit_bias,omitempty"`
+	User             string             `json:"user,omitempty"`
+	// Logprobs mirrors OpenAI's request schema (the number of most-likely
+	// tokens to report per position). Not implemented yet: requesting it
+	// doesn't error, but Choices[].Logprobs is always null in the response.
+	Logprobs *int `json:"logprobs,omitempty"`
+}
+
+// OpenAIUsage reports token accounting for a completion.
+type OpenAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// OpenAIToolCall describes a single tool invocation the model requested, in
+// OpenAI's function-calling response schema. No chat template in this
+// runtime parses tool calls out of model output yet, so this type exists
+// for schema compatibility but a populated slice never occurs today.
+type OpenAIToolCall struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Function OpenAIToolCallFunc `json:"function"`
+}
+
+// OpenAIToolCallFunc is the body of an OpenAIToolCall.
+type OpenAIToolCallFunc struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// OpenAIChatChoice is one entry in OpenAIChatResponse.Choices.
+type OpenAIChatChoice struct {
+	Index   int         `json:"index"`
+	Message ChatMessage `json:"message"`
+	// Logprobs is always null: token log-probabilities aren't implemented
+	// yet regardless of whether the request asked for them.
+	Logprobs     any    `json:"logprobs"`
+	FinishReason string `json:"finish_reason"`
+}
+
+// OpenAIChatResponse is the non-streaming response for POST /v1/chat/completions.
+type OpenAIChatResponse struct {
+	ID      string             `json:"id"`
+	Object  string             `json:"object"`
+	Created int64              `json:"created"`
+	Model   string             `json:"model"`
+	Choices []OpenAIChatChoice `json:"choices"`
+	Usage   OpenAIUsage        `json:"usage"`
+}
+
+// OpenAIChatDelta carries the incremental fields of a streamed chat chunk.
+type OpenAIChatDelta struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// OpenAIChatChunkChoice is one entry in OpenAIChatChunk.Choices.
+type OpenAIChatChunkChoice struct {
+	Index        int             `json:"index"`
+	Delta        OpenAIChatDelta `json:"delta"`
+	FinishReason *string         `json:"finish_reason"`
+}
+
+// OpenAIChatChunk is a single `data: {...}` frame streamed by
+// POST /v1/chat/completions when stream=true.
+type OpenAIChatChunk struct {
+	ID      string                  `json:"id"`
+	Object  string                  `json:"object"`
+	Created int64                   `json:"created"`
+	Model   string                  `json:"model"`
+	Choices []OpenAIChatChunkChoice `json:"choices"`
+}
+
+// OpenAICompletionChoice is one entry in OpenAICompletionResponse.Choices.
+type OpenAICompletionChoice struct {
+	Index int    `json:"index"`
+	Text  string `json:"text"`
+	// Logprobs is always null: see OpenAICompletionRequest.Logprobs.
+	Logprobs     any    `json:"logprobs"`
+	FinishReason string `json:"finish_reason"`
+}
+
+// OpenAICompletionResponse is the non-streaming response for POST /v1/completions.
+type OpenAICompletionResponse struct {
+	ID      string                   `json:"id"`
+	Object  string                   `json:"object"`
+	Created int64                    `json:"created"`
+	Model   string                   `json:"model"`
+	Choices []OpenAICompletionChoice `json:"choices"`
+	Usage   OpenAIUsage              `json:"usage"`
+}
+
+// OpenAICompletionChunk is a single `data: {...}` frame streamed by
+// POST /v1/completions when stream=true.
+type OpenAICompletionChunk struct {
+	ID      string                   `json:"id"`
+	Object  string                   `json:"object"`
+	Created int64                    `json:"created"`
+	Model   string                   `json:"model"`
+	Choices []OpenAICompletionChoice `json:"choices"`
+}
+
+// EmbeddingInput accepts either a single string or an array of strings for
+// the OpenAI-style "input" field and normalizes it to a slice, the same way
+// StopSequences does for "stop".
+type EmbeddingInput []string
+
+// UnmarshalJSON implements json.Unmarshaler, accepting both `"text"` and
+// `["a", "b"]` shapes.
+func (e *EmbeddingInput) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*e = EmbeddingInput{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*e = multi
+	return nil
+}
+
+// OpenAIEmbeddingRequest is the JSON body for POST /v1/embeddings.
+type OpenAIEmbeddingRequest struct {
+	Model string         `json:"model"`
+	Input EmbeddingInput `json:"input"`
+	User  string         `json:"user,omitempty"`
+}
+
+// OpenAIEmbeddingData is one entry in OpenAIEmbeddingResponse.Data,
+// corresponding to one string in the request's Input.
+type OpenAIEmbeddingData struct {
+	Object    string    `json:"object"`
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+// OpenAIEmbeddingResponse is the response for POST /v1/embeddings.
+type OpenAIEmbeddingResponse struct {
+	Object string                `json:"object"`
+	Model  string                `json:"model"`
+	Data   []OpenAIEmbeddingData `json:"data"`
+	Usage  OpenAIEmbeddingUsage  `json:"usage"`
+}
+
+// OpenAIEmbeddingUsage reports token accounting for an embeddings request,
+// which (unlike completions) has no completion_tokens component.
+type OpenAIEmbeddingUsage struct {
+	PromptTokens int `json:"prompt_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// OpenAIModel describes a single entry in GET /v1/models.
+type OpenAIModel struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+// OpenAIModelsResponse is the response for GET /v1/models.
+type OpenAIModelsResponse struct {
+	Object string        `json:"object"`
+	Data   []OpenAIModel `json:"data"`
+}
@@ -9,4 +9,14 @@ func RegisterRoutes(mux *http.ServeMux, s *Server) {
 	mux.HandleFunc("GET /api/tags", s.handleListModels)
 	mux.HandleFunc("DELETE /api/delete", s.handleDeleteModel)
 	mux.HandleFunc("GET /api/health", s.handleHealth)
+	mux.HandleFunc("GET /api/ps", s.handlePS)
+
+	// OpenAI-compatible surface, so existing OpenAI SDKs can talk to the
+	// runtime without an Ollama shim.
+	mux.HandleFunc("POST /v1/chat/completions", s.handleOpenAIChat)
+	mux.HandleFunc("POST /v1/completions", s.handleOpenAICompletions)
+	mux.HandleFunc("POST /v1/embeddings", s.handleOpenAIEmbeddings)
+	mux.HandleFunc("GET /v1/models", s.handleOpenAIModels)
+
+	mux.Handle("GET /metrics", s.metrics.Handler())
 }
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudchase/inference-runtime/backend"
+	"github.com/cloudchase/inference-runtime/bindings"
+	"github.com/cloudchase/inference-runtime/engine"
+	"github.com/cloudchase/inference-runtime/metrics"
+	"github.com/cloudchase/inference-runtime/registry"
+)
+
+// SchedulerConfig controls how the scheduler sizes its engine pool and
+// admits work onto it.
+type SchedulerConfig struct {
+	// NumParallel is the number of engine.Engine instances in the pool.
+	// Each instance owns one bindings.Context (and therefore one KV cache),
+	// so this bounds how many generations can run at once.
+	NumParallel int
+	// MaxQueue is the number of requests allowed to wait for a free engine
+	// before Acquire fails fast with an error instead of blocking forever.
+	MaxQueue int
+	// QueueTimeout bounds how long Acquire will wait for a free engine. Zero
+	// means wait as long as the caller's context allows.
+	QueueTimeout time.Duration
+}
+
+// DefaultSchedulerConfig returns single-model, single-engine defaults that
+// match the runtime's historical (pre-scheduler) behavior.
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		NumParallel: 1,
+		MaxQueue:    64,
+	}
+}
+
+// engineSlot is one pool member: an engine plus bookkeeping about which
+// model is currently loaded into it. Only the goroutine holding the slot
+// (between Acquire and Release) calls the engine; Model/setModel use a
+// mutex because GET /api/ps reads it concurrently.
+type engineSlot struct {
+	eng *engine.Engine
+
+	mu    sync.RWMutex
+	model string
+}
+
+// Model returns the name of the model currently resident in this slot, or
+// "" if none has been loaded yet.
+func (s *engineSlot) Model() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.model
+}
+
+func (s *engineSlot) setModel(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.model = name
+}
+
+// EngineLease is a held claim on one pooled engine, returned by
+// Scheduler.Acquire. The caller must call Release exactly once, typically
+// via defer, to return the engine to the pool.
+type EngineLease struct {
+	sched *Scheduler
+	slot  *engineSlot
+}
+
+// Engine returns the leased engine, already loaded with the requested model.
+func (l *EngineLease) Engine() *engine.Engine { return l.slot.eng }
+
+// Release returns the engine to the pool so another request can use it.
+func (l *EngineLease) Release() {
+	l.sched.free <- l.slot
+}
+
+// Scheduler admits generation requests onto a bounded pool of engines. It
+// replaces a single global mutex with leased engines: each request takes
+// whichever engine is free first and, if that engine holds a different
+// model, loads the requested one into it (an idle engine that already holds
+// the model is not preferred over other idle engines). Requests beyond pool
+// capacity wait in one bounded queue shared by all models instead of
+// starving or stomping on an in-flight generation's KV cache.
+type Scheduler struct {
+	manager *registry.ModelManager
+	cfg     SchedulerConfig
+	slots   []*engineSlot
+	free    chan *engineSlot
+	metrics *metrics.Registry
+
+	mu         sync.Mutex
+	queueDepth int
+}
+
+// SetMetrics attaches a metrics registry the scheduler reports queue depth
+// and model-loaded state to. Passing nil disables reporting; a nil
+// Scheduler.metrics is also safe to call through.
+func (s *Scheduler) SetMetrics(m *metrics.Registry) {
+	s.metrics = m
+}
+
+func (s *Scheduler) setQueueDepthMetric(n int) {
+	if s.metrics != nil {
+		s.metrics.SetQueueDepth(n)
+	}
+}
+
+// NewScheduler creates a Scheduler backed by cfg.NumParallel engine
+// instances, all using the given compute backend.
+func NewScheduler(mgr *registry.ModelManager, backend bindings.BackendType, cfg SchedulerConfig) (*Scheduler, error) {
+	if cfg.NumParallel <= 0 {
+		cfg.NumParallel = 1
+	}
+	if cfg.MaxQueue <= 0 {
+		cfg.MaxQueue = 64
+	}
+
+	slots := make([]*engineSlot, 0, cfg.NumParallel)
+	free := make(chan *engineSlot, cfg.NumParallel)
+	for i := 0; i < cfg.NumParallel; i++ {
+		eng, err := engine.NewWithBackend(backend)
+		if err != nil {
+			return nil, fmt.Errorf("init engine %d/%d: %w", i+1, cfg.NumParallel, err)
+		}
+		slot := &engineSlot{eng: eng}
+		slots = append(slots, slot)
+		free <- slot
+	}
+
+	return &Scheduler{
+		manager: mgr,
+		cfg:     cfg,
+		slots:   slots,
+		free:    free,
+	}, nil
+}
+
+// Acquire waits for a free engine, loads model into it if it isn't already
+// resident, and returns a lease the caller holds for the duration of one
+// generation. It returns an error immediately if the queue is already at
+// cfg.MaxQueue, and returns ctx's error if ctx (optionally narrowed by
+// cfg.QueueTimeout) is done before an engine becomes free.
+func (s *Scheduler) Acquire(ctx context.Context, model string) (*EngineLease, error) {
+	s.mu.Lock()
+	if s.queueDepth >= s.cfg.MaxQueue {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("scheduler queue full (max_queue=%d)", s.cfg.MaxQueue)
+	}
+	s.queueDepth++
+	s.setQueueDepthMetric(s.queueDepth)
+	s.mu.Unlock()
+
+	if s.cfg.QueueTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.QueueTimeout)
+		defer cancel()
+	}
+
+	var slot *engineSlot
+	select {
+	case slot = <-s.free:
+	case <-ctx.Done():
+		s.mu.Lock()
+		s.queueDepth--
+		s.setQueueDepthMetric(s.queueDepth)
+		s.mu.Unlock()
+		return nil, ctx.Err()
+	}
+
+	s.mu.Lock()
+	s.queueDepth--
+	s.setQueueDepthMetric(s.queueDepth)
+	s.mu.Unlock()
+
+	if slot.Model() != model {
+		modelPath, err := s.manager.ResolveModelPath(model)
+		if err != nil {
+			s.free <- slot
+			return nil, fmt.Errorf("cannot resolve model: %w", err)
+		}
+		manifest, _ := s.manager.GetModel(model) // best-effort; nil is fine for a raw path
+		if slot.eng.IsLoaded() {
+			if err := slot.eng.Reset(); err != nil {
+				s.free <- slot
+				return nil, fmt.Errorf("reset failed: %w", err)
+			}
+		}
+		previous := slot.Model()
+		if err := slot.eng.LoadModel(modelPath, backend.NameFor(manifest)); err != nil {
+			s.free <- slot
+			return nil, fmt.Errorf("load model: %w", err)
+		}
+		slot.setModel(model)
+		if s.metrics != nil {
+			stillLoaded := false
+			for _, other := range s.slots {
+				if other.Model() == previous {
+					stillLoaded = true
+					break
+				}
+			}
+			if previous != "" && !stillLoaded {
+				s.metrics.SetModelLoaded(previous, false)
+			}
+			s.metrics.SetModelLoaded(model, true)
+		}
+	}
+
+	return &EngineLease{sched: s, slot: slot}, nil
+}
+
+// QueueDepth returns the number of requests currently waiting for a free
+// engine.
+func (s *Scheduler) QueueDepth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queueDepth
+}
+
+// LoadedModels returns the model currently resident in each engine slot,
+// omitting slots that haven't loaded anything yet.
+func (s *Scheduler) LoadedModels() []string {
+	models := make([]string, 0, len(s.slots))
+	for _, slot := range s.slots {
+		if m := slot.Model(); m != "" {
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+// Close releases every engine in the pool. It does not wait for in-flight
+// leases to finish.
+func (s *Scheduler) Close() {
+	for _, slot := range s.slots {
+		slot.eng.Close()
+	}
+}
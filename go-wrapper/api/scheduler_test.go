@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestScheduler builds a Scheduler whose engine pool never yields a
+// slot, bypassing NewScheduler (which always fills the pool with at least
+// one free engine). That's enough to exercise Acquire's queue-admission and
+// queueDepth bookkeeping concurrently, without touching the FFI layer.
+func newTestScheduler(maxQueue int) *Scheduler {
+	return &Scheduler{
+		cfg:  SchedulerConfig{MaxQueue: maxQueue},
+		free: make(chan *engineSlot),
+	}
+}
+
+// TestScheduler_MaxQueue checks that Acquire rejects a request outright
+// once cfg.MaxQueue callers are already waiting for a free engine, rather
+// than letting an unbounded number of goroutines pile up.
+func TestScheduler_MaxQueue(t *testing.T) {
+	s := newTestScheduler(3)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	start := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			_, err := s.Acquire(ctx, "model")
+			errs <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != context.DeadlineExceeded {
+			t.Errorf("queued Acquire: got %v, want context.DeadlineExceeded", err)
+		}
+	}
+
+	// The queue should have drained back to empty once every waiter's
+	// context expired and it gave up.
+	if depth := s.QueueDepth(); depth != 0 {
+		t.Errorf("QueueDepth() after all waiters gave up = %d, want 0", depth)
+	}
+
+	// A MaxQueue+1th caller arriving while the others are still waiting
+	// must fail immediately rather than queue.
+	s2 := newTestScheduler(1)
+	blocked := make(chan struct{})
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		close(blocked)
+		_, _ = s2.Acquire(ctx, "model")
+	}()
+	<-blocked
+	// Give the blocked goroutine a moment to register itself in the queue.
+	for s2.QueueDepth() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := s2.Acquire(context.Background(), "model"); err == nil {
+		t.Error("Acquire with queue already at MaxQueue: want error, got nil")
+	}
+}
+
+// TestScheduler_QueueDepthConcurrent drives many concurrent Acquire/cancel
+// cycles and checks QueueDepth settles back to zero, guarding against a
+// race or leak in the queueDepth increment/decrement pairing.
+func TestScheduler_QueueDepthConcurrent(t *testing.T) {
+	s := newTestScheduler(50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			_, _ = s.Acquire(ctx, "model")
+		}()
+	}
+	wg.Wait()
+
+	if depth := s.QueueDepth(); depth != 0 {
+		t.Errorf("QueueDepth() after all goroutines finished = %d, want 0", depth)
+	}
+}
@@ -3,34 +3,60 @@ package api
 import (
 	"log"
 	"net/http"
-	"sync"
 
-	"github.com/cloudchase/inference-runtime/engine"
+	"github.com/cloudchase/inference-runtime/metrics"
 	"github.com/cloudchase/inference-runtime/registry"
 )
 
 // Server is the HTTP API server for the inference runtime.
 type Server struct {
-	engine  *engine.Engine
-	manager *registry.ModelManager
-	addr    string
-	mu      sync.Mutex // guards model loading
+	scheduler *Scheduler
+	manager   *registry.ModelManager
+	metrics   *metrics.Registry
+	addr      string
+	// metricsAddr, if set, also serves GET /metrics on a separate listener,
+	// in addition to the main addr.
+	metricsAddr string
 }
 
-// NewServer creates a new API server.
-func NewServer(eng *engine.Engine, mgr *registry.ModelManager, addr string) *Server {
+// NewServer creates a new API server backed by the given scheduler. The
+// scheduler is wired to metrics so its queue-depth and model-loaded gauges
+// get reported without the handlers needing to know about it.
+func NewServer(sched *Scheduler, mgr *registry.ModelManager, addr string) *Server {
+	reg := metrics.NewRegistry()
+	sched.SetMetrics(reg)
 	return &Server{
-		engine:  eng,
-		manager: mgr,
-		addr:    addr,
+		scheduler: sched,
+		manager:   mgr,
+		metrics:   reg,
+		addr:      addr,
 	}
 }
 
+// WithMetricsAddr configures Start to additionally serve GET /metrics on a
+// dedicated listener, so metrics scraping can be isolated from inference
+// traffic (e.g. behind a different network policy).
+func (s *Server) WithMetricsAddr(addr string) *Server {
+	s.metricsAddr = addr
+	return s
+}
+
 // Start registers routes and starts the HTTP server (blocking).
 func (s *Server) Start() error {
 	mux := http.NewServeMux()
 	RegisterRoutes(mux, s)
 
+	if s.metricsAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("GET /metrics", s.metrics.Handler())
+		go func() {
+			log.Printf("Starting metrics server on %s", s.metricsAddr)
+			if err := http.ListenAndServe(s.metricsAddr, metricsMux); err != nil {
+				log.Printf("metrics server error: %v", err)
+			}
+		}()
+	}
+
 	log.Printf("Starting inference-runtime API server on %s", s.addr)
-	return http.ListenAndServe(s.addr, mux)
+	return http.ListenAndServe(s.addr, LoggingMiddleware(mux))
 }
@@ -9,6 +9,10 @@ type GenerateRequest struct {
 	TopK        int     `json:"top_k,omitempty"`
 	TopP        float64 `json:"top_p,omitempty"`
 	Stream      bool    `json:"stream,omitempty"`
+	// RequestTimeout bounds total generation time, e.g. "30s". Parsed with
+	// time.ParseDuration; an empty value means no server-side bound beyond
+	// the client's own transport timeout.
+	RequestTimeout string `json:"request_timeout,omitempty"`
 }
 
 // GenerateResponse is the JSON response for POST /api/generate.
@@ -22,6 +26,10 @@ type GenerateResponse struct {
 type ChatMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	// ToolCalls carries tool invocations the model requested, in OpenAI's
+	// function-calling response schema. Always empty today: see
+	// OpenAIToolCall.
+	ToolCalls []OpenAIToolCall `json:"tool_calls,omitempty"`
 }
 
 // ChatRequest is the JSON body for POST /api/chat.
@@ -29,6 +37,35 @@ type ChatRequest struct {
 	Model    string        `json:"model"`
 	Messages []ChatMessage `json:"messages"`
 	Stream   bool          `json:"stream,omitempty"`
+	// RequestTimeout bounds total generation time; see GenerateRequest.
+	RequestTimeout string `json:"request_timeout,omitempty"`
+	// Template overrides the model's own chat template for this request. It
+	// is Jinja2 chat-template source, translated by templates.Render the
+	// same way as a GGUF tokenizer.chat_template. Empty (or untranslatable)
+	// means use the model's registry.ModelManifest.ChatTemplate, falling
+	// back to an architecture-matched or generic role-prefixed rendering.
+	Template string `json:"template,omitempty"`
+	// Tools lists functions the model may call. Only chat templates that
+	// support tool calling make use of this.
+	Tools []ToolDefinition `json:"tools,omitempty"`
+	// ToolChoice mirrors OpenAI's "auto"/"none"/tool-name field. It is
+	// accepted but not read: it is not passed to chat-template rendering
+	// and does not affect whether the model calls a tool.
+	ToolChoice string `json:"tool_choice,omitempty"`
+}
+
+// ToolDefinition describes a callable function for tool-calling-capable
+// chat templates, mirroring the OpenAI function-calling schema.
+type ToolDefinition struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction is the body of a ToolDefinition.
+type ToolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
 }
 
 // ChatResponse is the JSON response for POST /api/chat.
@@ -60,3 +97,9 @@ type ErrorResponse struct {
 type DeleteRequest struct {
 	Name string `json:"name"`
 }
+
+// PSResponse is the JSON response for GET /api/ps.
+type PSResponse struct {
+	Models     []string `json:"models"`
+	QueueDepth int      `json:"queue_depth"`
+}
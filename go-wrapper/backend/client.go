@@ -0,0 +1,181 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/cloudchase/inference-runtime/proto"
+)
+
+// Client talks to one backend process over its Unix socket. Each call opens
+// a short-lived connection, matching the one-call-per-connection framing in
+// wire.go; this keeps the client trivially safe for concurrent use at the
+// cost of a socket round-trip per call, which is negligible next to
+// generation latency.
+type Client struct {
+	sockPath string
+	proc     *process // nil for a client dialed to an externally-managed backend
+}
+
+func newClient(sockPath string, proc *process) *Client {
+	return &Client{sockPath: sockPath, proc: proc}
+}
+
+// dial opens a connection for one call, applying ctx's deadline (if any)
+// and closing the connection early if ctx is cancelled before the call
+// finishes naturally — the same cancellation shape bindings.Context uses
+// for the in-process FFI calls this client replaces.
+func (c *Client) dial(ctx context.Context) (*frameConn, func(), error) {
+	conn, err := net.Dial("unix", c.sockPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dial backend: %w", err)
+	}
+	if dl, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(dl)
+	}
+
+	doneCh := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-doneCh:
+		}
+	}()
+
+	return newFrameConn(conn), func() { close(doneCh) }, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, reqPayload, respPayload any) error {
+	fc, stopWatch, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer fc.Close()
+	defer stopWatch()
+
+	payload, err := json.Marshal(reqPayload)
+	if err != nil {
+		return fmt.Errorf("encode %s request: %w", method, err)
+	}
+	if err := fc.writeFrame(frame{Method: method, Payload: payload}); err != nil {
+		return firstErr(ctx, err)
+	}
+
+	resp, err := fc.readFrame()
+	if err != nil {
+		return firstErr(ctx, err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("backend: %s", resp.Error)
+	}
+	if respPayload == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Payload, respPayload)
+}
+
+// firstErr prefers ctx's error over err when ctx is what actually caused
+// the I/O failure (e.g. the cancellation watcher above closing the conn).
+func firstErr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+func (c *Client) Load(ctx context.Context, req *proto.LoadRequest) (*proto.LoadResponse, error) {
+	var resp proto.LoadResponse
+	if err := c.call(ctx, "Load", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) Generate(ctx context.Context, req *proto.GenerateRequest) (*proto.GenerateResponse, error) {
+	var resp proto.GenerateResponse
+	if err := c.call(ctx, "Generate", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GenerateStream streams tokens for req, calling recv for each one. It stops
+// reading, without error, as soon as recv returns false, and stops with
+// ctx.Err() if ctx is done before the stream finishes.
+func (c *Client) GenerateStream(ctx context.Context, req *proto.GenerateRequest, recv func(*proto.TokenChunk) bool) error {
+	fc, stopWatch, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer fc.Close()
+	defer stopWatch()
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("encode GenerateStream request: %w", err)
+	}
+	if err := fc.writeFrame(frame{Method: "GenerateStream", Payload: payload}); err != nil {
+		return firstErr(ctx, err)
+	}
+
+	for {
+		f, err := fc.readFrame()
+		if err != nil {
+			return firstErr(ctx, err)
+		}
+		if f.Done {
+			if f.Error != "" {
+				return fmt.Errorf("backend: %s", f.Error)
+			}
+			return nil
+		}
+		var chunk proto.TokenChunk
+		if err := json.Unmarshal(f.Payload, &chunk); err != nil {
+			return fmt.Errorf("decode token chunk: %w", err)
+		}
+		if !recv(&chunk) {
+			return nil
+		}
+	}
+}
+
+func (c *Client) Embed(ctx context.Context, req *proto.EmbedRequest) (*proto.EmbedResponse, error) {
+	var resp proto.EmbedResponse
+	if err := c.call(ctx, "Embed", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) Tokenize(ctx context.Context, req *proto.TokenizeRequest) (*proto.TokenizeResponse, error) {
+	var resp proto.TokenizeResponse
+	if err := c.call(ctx, "Tokenize", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) Reset(ctx context.Context) error {
+	return c.call(ctx, "Reset", &proto.ResetRequest{}, &proto.ResetResponse{})
+}
+
+func (c *Client) Health(ctx context.Context) (*proto.HealthResponse, error) {
+	var resp proto.HealthResponse
+	if err := c.call(ctx, "Health", &proto.HealthRequest{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Close terminates the backend process if this Client spawned it. A Client
+// holds no open connection between calls, so for an externally-managed
+// backend Close does nothing.
+func (c *Client) Close() error {
+	if c.proc != nil {
+		return c.proc.stop()
+	}
+	return nil
+}
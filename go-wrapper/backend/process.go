@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudchase/inference-runtime/registry"
+)
+
+// process owns a spawned backend executable and the socket it listens on.
+type process struct {
+	cmd      *exec.Cmd
+	sockPath string
+}
+
+func (p *process) stop() error {
+	os.Remove(p.sockPath)
+	if p.cmd == nil || p.cmd.Process == nil {
+		return nil
+	}
+	return p.cmd.Process.Kill()
+}
+
+// runDir returns the directory backend sockets are created in.
+func runDir() string {
+	return filepath.Join(registry.DefaultBaseDir(), "run")
+}
+
+// Spawn starts name's backend executable (resolved through reg) listening
+// on a fresh Unix socket, waits for it to come up, and returns a Client
+// connected to it. Closing the returned Client also terminates the process.
+func Spawn(reg *Registry, name string) (*Client, error) {
+	execPath, err := reg.Executable(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(runDir(), 0755); err != nil {
+		return nil, fmt.Errorf("create backend run dir: %w", err)
+	}
+	sockPath := filepath.Join(runDir(), fmt.Sprintf("%s-%d.sock", name, os.Getpid()))
+	os.Remove(sockPath) // stale socket from a previous unclean shutdown
+
+	cmd := exec.Command(execPath, "--socket", sockPath)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start backend %q: %w", name, err)
+	}
+
+	if err := waitForSocket(sockPath, 10*time.Second); err != nil {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("backend %q did not come up: %w", name, err)
+	}
+
+	return newClient(sockPath, &process{cmd: cmd, sockPath: sockPath}), nil
+}
+
+// waitForSocket polls for sockPath to accept connections, up to timeout.
+func waitForSocket(sockPath string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("unix", sockPath, 100*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		lastErr = err
+		time.Sleep(50 * time.Millisecond)
+	}
+	return lastErr
+}
@@ -0,0 +1,82 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cloudchase/inference-runtime/registry"
+)
+
+// Registry resolves a backend name ("llama", "whisper",
+// "stable-diffusion", ...) to the executable that serves it, so the engine
+// can pick a backend per model instead of assuming llama.cpp for
+// everything. Bundled backends are found next to the running ir binary;
+// anything else is looked up as "ir-backend-<name>" under
+// ~/.inference-runtime/backends/, letting third parties drop in their own.
+type Registry struct {
+	mu         sync.Mutex
+	executable map[string]string // backend name -> executable name override
+	searchDirs []string
+}
+
+// DefaultRegistry returns a Registry seeded with the runtime's bundled
+// llama.cpp backend and the user's external-backends directory.
+func DefaultRegistry() *Registry {
+	return &Registry{
+		executable: map[string]string{"llama": "ir-backend-llama"},
+		searchDirs: []string{filepath.Join(registry.DefaultBaseDir(), "backends")},
+	}
+}
+
+// Register overrides the executable name used to serve a backend. Useful
+// for tests or for wiring up a backend with a non-standard binary name.
+func (r *Registry) Register(name, executableName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.executable[name] = executableName
+}
+
+// NameFor returns which backend should serve m: m.Backend if set, otherwise
+// m.Architecture, otherwise "llama". A nil manifest (a model resolved by
+// raw path rather than registry name) also defaults to "llama".
+func NameFor(m *registry.ModelManifest) string {
+	if m == nil {
+		return "llama"
+	}
+	if m.Backend != "" {
+		return m.Backend
+	}
+	if m.Architecture != "" {
+		return m.Architecture
+	}
+	return "llama"
+}
+
+// Executable resolves name to an absolute executable path: first a bundled
+// backend next to the running ir binary, then an external one under
+// ~/.inference-runtime/backends/.
+func (r *Registry) Executable(name string) (string, error) {
+	r.mu.Lock()
+	execName, ok := r.executable[name]
+	searchDirs := append([]string(nil), r.searchDirs...)
+	r.mu.Unlock()
+	if !ok {
+		execName = "ir-backend-" + name
+	}
+
+	if self, err := os.Executable(); err == nil {
+		candidate := filepath.Join(filepath.Dir(self), execName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	for _, dir := range searchDirs {
+		candidate := filepath.Join(dir, execName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no executable found for backend %q (looked for %q next to ir and in %v)", name, execName, searchDirs)
+}
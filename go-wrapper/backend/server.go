@@ -0,0 +1,149 @@
+// Package backend dispatches model-shaped work (Generate, Embed, Tokenize,
+// ...) to a separate backend process over a Unix socket, per the contract in
+// proto.Backend / backend.proto. The transport is a hand-rolled
+// line-delimited JSON protocol (wire.go), not gRPC — see the proto package
+// doc comment for why.
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/cloudchase/inference-runtime/proto"
+)
+
+// Serve accepts connections on ln and dispatches each one's request frame to
+// impl, blocking until ln is closed. Each connection carries exactly one
+// call: a request frame in, either one response frame (unary) or a sequence
+// of TokenChunk frames terminated by a Done frame (GenerateStream) back.
+func Serve(ln net.Listener, impl proto.Backend) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			if err := handleConn(newFrameConn(conn), impl); err != nil {
+				log.Printf("backend: connection error: %v", err)
+			}
+			conn.Close()
+		}()
+	}
+}
+
+func handleConn(fc *frameConn, impl proto.Backend) error {
+	req, err := fc.readFrame()
+	if err != nil {
+		return fmt.Errorf("read request: %w", err)
+	}
+
+	ctx := context.Background()
+	if req.Method == "Generate" || req.Method == "GenerateStream" {
+		if dl, ok := deadlineFromPayload(req.Payload); ok {
+			var dlCancel context.CancelFunc
+			ctx, dlCancel = context.WithDeadline(ctx, dl)
+			defer dlCancel()
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Client.dial closes its connection as soon as its caller's ctx is
+	// done, rather than waiting for the call to finish naturally — watch
+	// for that here so a client disconnect/timeout cancels ctx on this
+	// side too, instead of leaving impl running to completion unobserved.
+	go func() {
+		var probe [1]byte
+		fc.conn.Read(probe[:])
+		cancel()
+	}()
+
+	if req.Method == "GenerateStream" {
+		var greq proto.GenerateRequest
+		if err := json.Unmarshal(req.Payload, &greq); err != nil {
+			return fc.writeFrame(frame{Error: err.Error()})
+		}
+		streamErr := impl.GenerateStream(ctx, &greq, func(chunk *proto.TokenChunk) bool {
+			payload, err := json.Marshal(chunk)
+			if err != nil {
+				return false
+			}
+			return fc.writeFrame(frame{Payload: payload}) == nil
+		})
+		if streamErr != nil {
+			return fc.writeFrame(frame{Error: streamErr.Error(), Done: true})
+		}
+		return fc.writeFrame(frame{Done: true})
+	}
+
+	resp, err := dispatchUnary(ctx, impl, req.Method, req.Payload)
+	if err != nil {
+		return fc.writeFrame(frame{Error: err.Error()})
+	}
+	payload, err := json.Marshal(resp)
+	if err != nil {
+		return fc.writeFrame(frame{Error: err.Error()})
+	}
+	return fc.writeFrame(frame{Payload: payload})
+}
+
+// deadlineFromPayload extracts a GenerateRequest's DeadlineUnixMs without
+// needing to know its full shape yet (that's decoded again, method-specific,
+// in dispatchUnary/handleConn's GenerateStream branch); a zero or unparsable
+// value means no deadline.
+func deadlineFromPayload(payload json.RawMessage) (time.Time, bool) {
+	var d struct {
+		DeadlineUnixMs uint64
+	}
+	if err := json.Unmarshal(payload, &d); err != nil || d.DeadlineUnixMs == 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(d.DeadlineUnixMs)), true
+}
+
+func dispatchUnary(ctx context.Context, impl proto.Backend, method string, payload json.RawMessage) (any, error) {
+	switch method {
+	case "Load":
+		var req proto.LoadRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		return impl.Load(ctx, &req)
+	case "Generate":
+		var req proto.GenerateRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		return impl.Generate(ctx, &req)
+	case "Embed":
+		var req proto.EmbedRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		return impl.Embed(ctx, &req)
+	case "Tokenize":
+		var req proto.TokenizeRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		return impl.Tokenize(ctx, &req)
+	case "Reset":
+		var req proto.ResetRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		return impl.Reset(ctx, &req)
+	case "Health":
+		var req proto.HealthRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		return impl.Health(ctx, &req)
+	default:
+		return nil, fmt.Errorf("unknown method %q", method)
+	}
+}
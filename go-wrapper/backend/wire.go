@@ -0,0 +1,54 @@
+package backend
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+)
+
+// frame is one line of the wire protocol: a single JSON object per
+// connection for unary calls, or a sequence of them (one per token, a final
+// one with Done set) for GenerateStream. Payload carries the
+// method-specific request or response, re-decoded by the caller once Method
+// (or Error) tells it which shape to expect.
+type frame struct {
+	Method  string          `json:"method,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Done    bool            `json:"done,omitempty"`
+}
+
+// frameConn wraps a net.Conn with line-delimited JSON framing.
+type frameConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func newFrameConn(conn net.Conn) *frameConn {
+	return &frameConn{conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (c *frameConn) writeFrame(f frame) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("encode frame: %w", err)
+	}
+	data = append(data, '\n')
+	_, err = c.conn.Write(data)
+	return err
+}
+
+func (c *frameConn) readFrame() (frame, error) {
+	line, err := c.r.ReadBytes('\n')
+	if err != nil {
+		return frame{}, err
+	}
+	var f frame
+	if err := json.Unmarshal(line, &f); err != nil {
+		return frame{}, fmt.Errorf("decode frame: %w", err)
+	}
+	return f, nil
+}
+
+func (c *frameConn) Close() error { return c.conn.Close() }
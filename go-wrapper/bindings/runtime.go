@@ -9,6 +9,7 @@ package bindings
 */
 import "C"
 import (
+	"context"
 	"fmt"
 	"runtime/cgo"
 	"unsafe"
@@ -36,23 +37,29 @@ const (
 
 // GenerateParams mirrors the C IRGenerateParams struct.
 type GenerateParams struct {
-	MaxTokens        uint32
-	Temperature      float32
-	TopK             uint32
-	TopP             float32
+	MaxTokens         uint32
+	Temperature       float32
+	TopK              uint32
+	TopP              float32
 	RepetitionPenalty float32
-	Seed             uint64
+	Seed              uint64
+	// DeadlineUnixMs is an absolute deadline (Unix millis) the FFI layer may
+	// use to abort generation early. Zero means no deadline.
+	DeadlineUnixMs uint64
+	// Stop is a set of strings that, once generated, end the run before
+	// MaxTokens is reached.
+	Stop []string
 }
 
 // DefaultGenerateParams returns sensible defaults for generation.
 func DefaultGenerateParams() GenerateParams {
 	return GenerateParams{
-		MaxTokens:        256,
-		Temperature:      0.8,
-		TopK:             40,
-		TopP:             0.95,
+		MaxTokens:         256,
+		Temperature:       0.8,
+		TopK:              40,
+		TopP:              0.95,
 		RepetitionPenalty: 1.1,
-		Seed:             0,
+		Seed:              0,
 	}
 }
 
@@ -92,17 +99,30 @@ func (c *Context) LoadModel(path string) error {
 }
 
 // Generate runs non-streaming generation and returns the full output.
-func (c *Context) Generate(prompt string, params GenerateParams) (string, error) {
+// If ctx is already done, Generate returns its error without calling into
+// the FFI layer; otherwise ctx's deadline (if any) is passed down so the
+// engine can abort a run that's taking too long.
+func (c *Context) Generate(ctx context.Context, prompt string, params GenerateParams) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
 	cPrompt := C.CString(prompt)
 	defer C.free(unsafe.Pointer(cPrompt))
 
+	stopPtr, stopCount, freeStop := cStringArray(params.Stop)
+	defer freeStop()
+
 	cParams := C.IRGenerateParams{
-		max_tokens:        C.uint32_t(params.MaxTokens),
-		temperature:       C.float(params.Temperature),
-		top_k:             C.uint32_t(params.TopK),
-		top_p:             C.float(params.TopP),
+		max_tokens:         C.uint32_t(params.MaxTokens),
+		temperature:        C.float(params.Temperature),
+		top_k:              C.uint32_t(params.TopK),
+		top_p:              C.float(params.TopP),
 		repetition_penalty: C.float(params.RepetitionPenalty),
-		seed:              C.uint64_t(params.Seed),
+		seed:               C.uint64_t(params.Seed),
+		deadline_unix_ms:   C.uint64_t(deadlineUnixMs(ctx, params)),
+		stop_sequences:     stopPtr,
+		stop_count:         stopCount,
 	}
 
 	var output *C.char
@@ -116,6 +136,40 @@ func (c *Context) Generate(prompt string, params GenerateParams) (string, error)
 	return result, nil
 }
 
+// deadlineUnixMs resolves the effective deadline for a call: an explicit
+// params.DeadlineUnixMs takes precedence, otherwise ctx's deadline (if set)
+// is used.
+func deadlineUnixMs(ctx context.Context, params GenerateParams) uint64 {
+	if params.DeadlineUnixMs != 0 {
+		return params.DeadlineUnixMs
+	}
+	if dl, ok := ctx.Deadline(); ok {
+		return uint64(dl.UnixMilli())
+	}
+	return 0
+}
+
+// cStringArray allocates a C array of *C.char from ss, the same way
+// C.CString allocates a single one. The returned free func releases the
+// whole array and must be called exactly once, typically via defer; it is
+// safe to call even when ss is empty, in which case no allocation happened.
+func cStringArray(ss []string) (**C.char, C.uint32_t, func()) {
+	if len(ss) == 0 {
+		return nil, 0, func() {}
+	}
+	arr := C.malloc(C.size_t(len(ss)) * C.size_t(unsafe.Sizeof((*C.char)(nil))))
+	cArr := unsafe.Slice((**C.char)(arr), len(ss))
+	for i, s := range ss {
+		cArr[i] = C.CString(s)
+	}
+	return (**C.char)(arr), C.uint32_t(len(ss)), func() {
+		for _, p := range cArr {
+			C.free(unsafe.Pointer(p))
+		}
+		C.free(arr)
+	}
+}
+
 // StreamCallback is called for each generated token. Return false to stop generation.
 type StreamCallback func(token string) bool
 
@@ -135,20 +189,55 @@ func goStreamCallback(token *C.char, userData unsafe.Pointer) C.bool {
 }
 
 // GenerateStreaming runs streaming generation, calling callback for each token.
-func (c *Context) GenerateStreaming(prompt string, params GenerateParams, callback StreamCallback) error {
+// It watches ctx for cancellation the same way net.Conn watches a deadline:
+// a goroutine waits on ctx.Done() and, if it fires before generation
+// finishes naturally, closes cancelCh so the next invocation of callback
+// returns false and the FFI loop unwinds.
+func (c *Context) GenerateStreaming(ctx context.Context, prompt string, params GenerateParams, callback StreamCallback) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	cPrompt := C.CString(prompt)
 	defer C.free(unsafe.Pointer(cPrompt))
 
+	stopPtr, stopCount, freeStop := cStringArray(params.Stop)
+	defer freeStop()
+
 	cParams := C.IRGenerateParams{
-		max_tokens:        C.uint32_t(params.MaxTokens),
-		temperature:       C.float(params.Temperature),
-		top_k:             C.uint32_t(params.TopK),
-		top_p:             C.float(params.TopP),
+		max_tokens:         C.uint32_t(params.MaxTokens),
+		temperature:        C.float(params.Temperature),
+		top_k:              C.uint32_t(params.TopK),
+		top_p:              C.float(params.TopP),
 		repetition_penalty: C.float(params.RepetitionPenalty),
-		seed:              C.uint64_t(params.Seed),
+		seed:               C.uint64_t(params.Seed),
+		deadline_unix_ms:   C.uint64_t(deadlineUnixMs(ctx, params)),
+		stop_sequences:     stopPtr,
+		stop_count:         stopCount,
+	}
+
+	cancelCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(cancelCh)
+		case <-doneCh:
+		}
+	}()
+
+	wrapped := func(token string) bool {
+		select {
+		case <-cancelCh:
+			return false
+		default:
+		}
+		return callback(token)
 	}
 
-	sh := &streamHandle{callback: callback}
+	sh := &streamHandle{callback: wrapped}
 	h := cgo.NewHandle(sh)
 	defer h.Delete()
 
@@ -160,11 +249,68 @@ func (c *Context) GenerateStreaming(prompt string, params GenerateParams, callba
 		unsafe.Pointer(uintptr(h)), //nolint:govet // cgo.Handle is a uintptr; this round-trip is safe
 	)
 	if status != C.IR_STATUS_OK {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		return fmt.Errorf("streaming generation failed: %s", LastError())
 	}
 	return nil
 }
 
+// Tokenize returns the token IDs the model's tokenizer produces for text,
+// without running generation.
+func (c *Context) Tokenize(text string) ([]int32, error) {
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+
+	var tokens *C.int32_t
+	var count C.uint32_t
+	status := C.ir_tokenize(c.ctx, cText, &tokens, &count)
+	if status != C.IR_STATUS_OK {
+		return nil, fmt.Errorf("tokenize failed: %s", LastError())
+	}
+	defer C.ir_tokenize_free(tokens)
+
+	out := make([]int32, count)
+	for i, t := range unsafe.Slice(tokens, count) {
+		out[i] = int32(t)
+	}
+	return out, nil
+}
+
+// Embed returns the model's embedding vector for text. It requires an
+// embedding-capable model; calling it against a text-generation-only model
+// returns an error from the FFI layer.
+func (c *Context) Embed(text string) ([]float32, error) {
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+
+	var vec *C.float
+	var count C.uint32_t
+	status := C.ir_embed(c.ctx, cText, &vec, &count)
+	if status != C.IR_STATUS_OK {
+		return nil, fmt.Errorf("embed failed: %s", LastError())
+	}
+	defer C.ir_embed_free(vec)
+
+	out := make([]float32, count)
+	for i, v := range unsafe.Slice(vec, count) {
+		out[i] = float32(v)
+	}
+	return out, nil
+}
+
+// KVCacheBytes returns the approximate size in bytes of the context's
+// current KV cache, for reporting via the ir_kv_cache_bytes gauge.
+func (c *Context) KVCacheBytes() (uint64, error) {
+	var out C.uint64_t
+	status := C.ir_kv_cache_bytes(c.ctx, &out)
+	if status != C.IR_STATUS_OK {
+		return 0, fmt.Errorf("kv cache bytes failed: %s", LastError())
+	}
+	return uint64(out), nil
+}
+
 // Reset clears the context's KV cache and state.
 func (c *Context) Reset() error {
 	status := C.ir_reset(c.ctx)
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cloudchase/inference-runtime/registry"
+	"github.com/spf13/cobra"
+)
+
+var galleryIndexURL string
+
+var galleryCmd = &cobra.Command{
+	Use:   "gallery",
+	Short: "Browse and install curated models from the model gallery",
+	Long: `Browse and install curated models from a gallery index, a YAML document
+listing models by name with download URLs, sha256 checksums, chat templates,
+and default sampler options.
+
+By default the gallery at ` + registry.DefaultGalleryURL + ` is used; pass
+--index to point at a different one.`,
+}
+
+var galleryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List models available in the gallery",
+	Args:  cobra.NoArgs,
+	RunE:  runGalleryList,
+}
+
+var gallerySearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the gallery by name or description",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGallerySearch,
+}
+
+var galleryInstallCmd = &cobra.Command{
+	Use:   "install <name>",
+	Short: "Download and register a gallery model",
+	Long: `Download a model named in the gallery index, verify it against the
+entry's published sha256, and register it locally under --as (or the
+gallery entry's own name, lowercased). Refuses to register a download whose
+sha256 doesn't match the gallery entry.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGalleryInstall,
+}
+
+var galleryInstallAs string
+
+func init() {
+	galleryCmd.PersistentFlags().StringVar(&galleryIndexURL, "index", "", "Gallery index URL (defaults to the built-in curated index)")
+	galleryInstallCmd.Flags().StringVar(&galleryInstallAs, "as", "", "Local model name to register under (defaults to the gallery entry's name)")
+
+	galleryCmd.AddCommand(galleryListCmd)
+	galleryCmd.AddCommand(gallerySearchCmd)
+	galleryCmd.AddCommand(galleryInstallCmd)
+}
+
+func printGalleryEntries(entries []registry.GalleryEntry) {
+	if len(entries) == 0 {
+		fmt.Println("No matching models.")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tLICENSE\tDESCRIPTION")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.Name, e.License, e.Description)
+	}
+	w.Flush()
+}
+
+func runGalleryList(_ *cobra.Command, _ []string) error {
+	mgr, err := registry.NewModelManager(registry.DefaultBaseDir())
+	if err != nil {
+		return fmt.Errorf("init model manager: %w", err)
+	}
+	entries, err := mgr.GalleryList(galleryIndexURL)
+	if err != nil {
+		return err
+	}
+	printGalleryEntries(entries)
+	return nil
+}
+
+func runGallerySearch(_ *cobra.Command, args []string) error {
+	mgr, err := registry.NewModelManager(registry.DefaultBaseDir())
+	if err != nil {
+		return fmt.Errorf("init model manager: %w", err)
+	}
+	entries, err := mgr.GallerySearch(galleryIndexURL, args[0])
+	if err != nil {
+		return err
+	}
+	printGalleryEntries(entries)
+	return nil
+}
+
+func runGalleryInstall(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	mgr, err := registry.NewModelManager(registry.DefaultBaseDir())
+	if err != nil {
+		return fmt.Errorf("init model manager: %w", err)
+	}
+
+	fmt.Printf("Installing %s...\n", name)
+
+	var lastPct int
+	progress := func(downloaded, total int64) {
+		if total <= 0 {
+			return
+		}
+		pct := int(downloaded * 100 / total)
+		if pct != lastPct {
+			fmt.Printf("\r%s  %d%%", name, pct)
+			lastPct = pct
+		}
+	}
+
+	if err := mgr.GalleryInstall(galleryIndexURL, name, galleryInstallAs, progress); err != nil {
+		fmt.Println()
+		return fmt.Errorf("install %s: %w", name, err)
+	}
+	fmt.Println()
+	fmt.Printf("Installed as %q.\n", localOrEntryName(galleryInstallAs, name))
+	return nil
+}
+
+// localOrEntryName mirrors the name GalleryInstall registers under, for
+// the success message.
+func localOrEntryName(as, name string) string {
+	if as != "" {
+		return as
+	}
+	return strings.ToLower(name)
+}
@@ -40,6 +40,9 @@ func runInfo(_ *cobra.Command, args []string) error {
 	if m.Quantization != "" {
 		fmt.Printf("Quantization:  %s\n", m.Quantization)
 	}
+	if m.SourceRepo != "" {
+		fmt.Printf("Source:        %s@%s (%s)\n", m.SourceRepo, m.SourceRevision, m.SourceFile)
+	}
 	fmt.Printf("Added:         %s\n", m.AddedAt.Format("2006-01-02 15:04:05"))
 
 	return nil
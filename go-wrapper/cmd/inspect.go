@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cloudchase/inference-runtime/registry"
+	"github.com/spf13/cobra"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <model>",
+	Short: "Show raw GGUF metadata",
+	Long:  "Display the raw GGUF header key/value metadata for a registered model or file path.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runInspect,
+}
+
+func runInspect(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	mgr, err := registry.NewModelManager(registry.DefaultBaseDir())
+	if err != nil {
+		return fmt.Errorf("init model manager: %w", err)
+	}
+
+	kv, err := mgr.Inspect(name)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("%-40s %v\n", k, kv[k])
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+// Command ir-backend-llama is the default backend process for GGUF models
+// running on the llama.cpp-based engine. It wraps the existing CGO bindings
+// and serves them over a Unix socket using the protocol in
+// github.com/cloudchase/inference-runtime/backend, so the main ir binary
+// never links the inference engine in-process.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/cloudchase/inference-runtime/backend"
+	"github.com/cloudchase/inference-runtime/bindings"
+	"github.com/cloudchase/inference-runtime/proto"
+)
+
+func main() {
+	sockPath := flag.String("socket", "", "Unix socket path to listen on")
+	flag.Parse()
+
+	if *sockPath == "" {
+		fmt.Fprintln(os.Stderr, "ir-backend-llama: --socket is required")
+		os.Exit(2)
+	}
+
+	os.Remove(*sockPath)
+	ln, err := net.Listen("unix", *sockPath)
+	if err != nil {
+		log.Fatalf("ir-backend-llama: listen on %s: %v", *sockPath, err)
+	}
+	defer ln.Close()
+
+	impl := &llamaBackend{}
+	log.Printf("ir-backend-llama: serving on %s", *sockPath)
+	if err := backend.Serve(ln, impl); err != nil {
+		log.Fatalf("ir-backend-llama: serve: %v", err)
+	}
+}
+
+// llamaBackend implements proto.Backend directly on top of bindings.Context,
+// the same FFI wrapper engine.Engine used to call in-process.
+type llamaBackend struct {
+	mu        sync.Mutex
+	ctx       *bindings.Context
+	modelPath string
+	loaded    bool
+}
+
+func (b *llamaBackend) Load(_ context.Context, req *proto.LoadRequest) (*proto.LoadResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ctx == nil {
+		backendType := bindings.BackendCPU
+		if req.ComputeBackend == proto.ComputeMetal {
+			backendType = bindings.BackendMetal
+		}
+		ctx, err := bindings.NewContext(backendType)
+		if err != nil {
+			return nil, fmt.Errorf("create context: %w", err)
+		}
+		b.ctx = ctx
+	}
+
+	if err := b.ctx.LoadModel(req.ModelPath); err != nil {
+		return nil, fmt.Errorf("load model: %w", err)
+	}
+	b.modelPath = req.ModelPath
+	b.loaded = true
+	return &proto.LoadResponse{}, nil
+}
+
+func (b *llamaBackend) Generate(ctx context.Context, req *proto.GenerateRequest) (*proto.GenerateResponse, error) {
+	if !b.loaded {
+		return nil, fmt.Errorf("no model loaded")
+	}
+	text, err := b.ctx.Generate(ctx, req.Prompt, toGenerateParams(req))
+	if err != nil {
+		return nil, err
+	}
+	return &proto.GenerateResponse{Text: text}, nil
+}
+
+func (b *llamaBackend) GenerateStream(ctx context.Context, req *proto.GenerateRequest, send func(*proto.TokenChunk) bool) error {
+	if !b.loaded {
+		return fmt.Errorf("no model loaded")
+	}
+	return b.ctx.GenerateStreaming(ctx, req.Prompt, toGenerateParams(req), func(token string) bool {
+		return send(&proto.TokenChunk{Token: token})
+	})
+}
+
+func (b *llamaBackend) Embed(_ context.Context, req *proto.EmbedRequest) (*proto.EmbedResponse, error) {
+	if !b.loaded {
+		return nil, fmt.Errorf("no model loaded")
+	}
+	vec, err := b.ctx.Embed(req.Text)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.EmbedResponse{Vector: vec}, nil
+}
+
+func (b *llamaBackend) Tokenize(_ context.Context, req *proto.TokenizeRequest) (*proto.TokenizeResponse, error) {
+	if !b.loaded {
+		return nil, fmt.Errorf("no model loaded")
+	}
+	tokens, err := b.ctx.Tokenize(req.Text)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.TokenizeResponse{Tokens: tokens}, nil
+}
+
+func (b *llamaBackend) Reset(_ context.Context, _ *proto.ResetRequest) (*proto.ResetResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ctx == nil {
+		return &proto.ResetResponse{}, nil
+	}
+	if err := b.ctx.Reset(); err != nil {
+		return nil, err
+	}
+	return &proto.ResetResponse{}, nil
+}
+
+func (b *llamaBackend) Health(_ context.Context, _ *proto.HealthRequest) (*proto.HealthResponse, error) {
+	resp := &proto.HealthResponse{Loaded: b.loaded, ModelPath: b.modelPath}
+	if b.loaded {
+		if kv, err := b.ctx.KVCacheBytes(); err == nil {
+			resp.KVCacheBytes = kv
+		}
+	}
+	return resp, nil
+}
+
+func toGenerateParams(req *proto.GenerateRequest) bindings.GenerateParams {
+	return bindings.GenerateParams{
+		MaxTokens:         req.MaxTokens,
+		Temperature:       req.Temperature,
+		TopK:              req.TopK,
+		TopP:              req.TopP,
+		RepetitionPenalty: req.RepetitionPenalty,
+		Seed:              req.Seed,
+		Stop:              req.Stop,
+		DeadlineUnixMs:    req.DeadlineUnixMs,
+	}
+}
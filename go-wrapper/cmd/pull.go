@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudchase/inference-runtime/registry"
+	"github.com/spf13/cobra"
+)
+
+var pullQuant string
+
+var pullCmd = &cobra.Command{
+	Use:   "pull <repo>[:file.gguf][@revision]",
+	Short: "Download a model from the HuggingFace Hub",
+	Long: `Download a GGUF model from the HuggingFace Hub and register it locally.
+
+<repo> is a HuggingFace repo reference, e.g. "TheBloke/Llama-2-7B-Chat-GGUF".
+Append ":file.gguf" to fetch an exact file, or "@revision" to pull a branch,
+tag, or commit other than the repo's default. If a repo publishes more than
+one quantization, use --quant to pick one (e.g. --quant Q4_K_M).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPull,
+}
+
+func init() {
+	pullCmd.Flags().StringVar(&pullQuant, "quant", "", "Quantization tag to select when a repo has multiple .gguf files (e.g. Q4_K_M)")
+}
+
+func runPull(_ *cobra.Command, args []string) error {
+	ref := args[0]
+	name := localNameFor(ref)
+
+	mgr, err := registry.NewModelManager(registry.DefaultBaseDir())
+	if err != nil {
+		return fmt.Errorf("init model manager: %w", err)
+	}
+
+	fmt.Printf("Pulling %s...\n", ref)
+
+	var lastPct int
+	opts := registry.PullOptions{
+		Quant: pullQuant,
+		Progress: func(downloaded, total int64) {
+			if total <= 0 {
+				return
+			}
+			pct := int(downloaded * 100 / total)
+			if pct != lastPct {
+				fmt.Printf("\r%s  %d%%", name, pct)
+				lastPct = pct
+			}
+		},
+	}
+
+	if err := mgr.Pull(name, ref, opts); err != nil {
+		fmt.Println()
+		return fmt.Errorf("pull %s: %w", ref, err)
+	}
+	fmt.Println()
+	fmt.Printf("Registered as %q.\n", name)
+	return nil
+}
+
+// localNameFor derives the local model name from a HuggingFace ref,
+// stripping the optional ":file"/"@revision" suffixes and the repo owner.
+func localNameFor(ref string) string {
+	repo := ref
+	if at := strings.LastIndex(repo, "@"); at != -1 {
+		repo = repo[:at]
+	}
+	if colon := strings.Index(repo, ":"); colon != -1 {
+		repo = repo[:colon]
+	}
+	if slash := strings.LastIndex(repo, "/"); slash != -1 {
+		repo = repo[slash+1:]
+	}
+	return strings.ToLower(repo)
+}
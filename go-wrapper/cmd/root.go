@@ -20,4 +20,7 @@ func init() {
 	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(galleryCmd)
 }
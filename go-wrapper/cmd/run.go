@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/cloudchase/inference-runtime/backend"
 	"github.com/cloudchase/inference-runtime/engine"
 	"github.com/cloudchase/inference-runtime/registry"
+	"github.com/cloudchase/inference-runtime/templates"
 	"github.com/spf13/cobra"
 )
 
@@ -38,6 +41,11 @@ func runRun(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	manifest, _ := mgr.GetModel(modelArg) // best-effort; nil is fine for a raw path
+	if manifest == nil {
+		manifest = &registry.ModelManifest{}
+	}
+	cfg, _ := mgr.GetConfig(modelArg) // best-effort; nil means no gallery config
 
 	eng, err := engine.New()
 	if err != nil {
@@ -46,30 +54,83 @@ func runRun(cmd *cobra.Command, args []string) error {
 	defer eng.Close()
 
 	fmt.Fprintf(os.Stderr, "Loading model: %s\n", modelPath)
-	if err := eng.LoadModel(modelPath); err != nil {
+	if err := eng.LoadModel(modelPath, backend.NameFor(manifest)); err != nil {
 		return err
 	}
 	fmt.Fprintln(os.Stderr, "Model loaded.")
 
-	opts := engine.DefaultOptions()
+	opts := applyConfigDefaults(engine.DefaultOptions(), cfg)
 
 	// Single-shot mode: generate and exit.
 	if prompt != "" {
-		return generateAndPrint(eng, prompt, opts)
+		rendered, err := renderRunPrompt(manifest, cfg, prompt)
+		if err != nil {
+			return err
+		}
+		return generateAndPrint(eng, rendered, opts)
 	}
 
 	// Interactive REPL mode.
-	return repl(eng, opts)
+	return repl(eng, manifest, cfg, opts)
+}
+
+// applyConfigDefaults overrides base with cfg's saved sampler options,
+// where set. cfg may be nil, meaning no gallery config was saved for this
+// model, in which case base is returned unchanged.
+func applyConfigDefaults(base engine.GenerateOptions, cfg *registry.ModelConfig) engine.GenerateOptions {
+	if cfg == nil {
+		return base
+	}
+	if cfg.MaxTokens > 0 {
+		base.MaxTokens = cfg.MaxTokens
+	}
+	if cfg.Temperature > 0 {
+		base.Temperature = cfg.Temperature
+	}
+	if cfg.TopK > 0 {
+		base.TopK = cfg.TopK
+	}
+	if cfg.TopP > 0 {
+		base.TopP = cfg.TopP
+	}
+	if cfg.RepetitionPenalty > 0 {
+		base.RepetitionPenalty = cfg.RepetitionPenalty
+	}
+	if len(cfg.Stop) > 0 {
+		base.Stop = cfg.Stop
+	}
+	return base
+}
+
+// renderRunPrompt wraps prompt in the model's chat template when one is
+// available (from its manifest or its gallery config's default system
+// prompt), so a gallery-installed model doesn't need --system repeated on
+// every invocation. Models with neither just generate from the raw prompt.
+func renderRunPrompt(manifest *registry.ModelManifest, cfg *registry.ModelConfig, prompt string) (string, error) {
+	system := ""
+	if cfg != nil {
+		system = cfg.System
+	}
+	if manifest.ChatTemplate == "" && system == "" {
+		return prompt, nil
+	}
+
+	var msgs []templates.Message
+	if system != "" {
+		msgs = append(msgs, templates.Message{Role: "system", Content: system})
+	}
+	msgs = append(msgs, templates.Message{Role: "user", Content: prompt})
+	return templates.Render(manifest, msgs, nil, "")
 }
 
 func generateAndPrint(eng *engine.Engine, prompt string, opts engine.GenerateOptions) error {
-	return eng.GenerateStream(prompt, opts, func(token string) bool {
+	return eng.GenerateStream(context.Background(), prompt, opts, func(token string) bool {
 		fmt.Print(token)
 		return true
 	})
 }
 
-func repl(eng *engine.Engine, opts engine.GenerateOptions) error {
+func repl(eng *engine.Engine, manifest *registry.ModelManifest, cfg *registry.ModelConfig, opts engine.GenerateOptions) error {
 	scanner := bufio.NewScanner(os.Stdin)
 	fmt.Print(">>> ")
 
@@ -102,7 +163,13 @@ func repl(eng *engine.Engine, opts engine.GenerateOptions) error {
 			continue
 		}
 
-		if err := generateAndPrint(eng, line, opts); err != nil {
+		rendered, err := renderRunPrompt(manifest, cfg, line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\nTemplate error: %v\n", err)
+			fmt.Print(">>> ")
+			continue
+		}
+		if err := generateAndPrint(eng, rendered, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "\nGeneration error: %v\n", err)
 		}
 		fmt.Println()
@@ -2,15 +2,20 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cloudchase/inference-runtime/api"
-	"github.com/cloudchase/inference-runtime/engine"
+	"github.com/cloudchase/inference-runtime/bindings"
 	"github.com/cloudchase/inference-runtime/registry"
 	"github.com/spf13/cobra"
 )
 
 var (
-	serveAddr string
+	serveAddr         string
+	serveMetricsAddr  string
+	serveNumParallel  int
+	serveMaxQueue     int
+	serveQueueTimeout time.Duration
 )
 
 var serveCmd = &cobra.Command{
@@ -22,6 +27,10 @@ var serveCmd = &cobra.Command{
 
 func init() {
 	serveCmd.Flags().StringVar(&serveAddr, "addr", ":11434", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveMetricsAddr, "metrics-addr", "", "Address to additionally serve Prometheus metrics on (metrics are always served on --addr too)")
+	serveCmd.Flags().IntVar(&serveNumParallel, "num_parallel", 1, "Number of concurrent generation slots (engine instances)")
+	serveCmd.Flags().IntVar(&serveMaxQueue, "max_queue", 64, "Maximum requests allowed to wait for a free engine slot")
+	serveCmd.Flags().DurationVar(&serveQueueTimeout, "queue_timeout", 0, "Maximum time a request will wait for a free engine slot before failing (0 waits as long as the caller's context allows)")
 }
 
 func runServe(_ *cobra.Command, _ []string) error {
@@ -30,12 +39,17 @@ func runServe(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("init model manager: %w", err)
 	}
 
-	eng, err := engine.New()
+	cfg := api.SchedulerConfig{
+		NumParallel:  serveNumParallel,
+		MaxQueue:     serveMaxQueue,
+		QueueTimeout: serveQueueTimeout,
+	}
+	sched, err := api.NewScheduler(mgr, bindings.BackendCPU, cfg)
 	if err != nil {
-		return fmt.Errorf("init engine: %w", err)
+		return fmt.Errorf("init scheduler: %w", err)
 	}
-	defer eng.Close()
+	defer sched.Close()
 
-	srv := api.NewServer(eng, mgr, serveAddr)
+	srv := api.NewServer(sched, mgr, serveAddr).WithMetricsAddr(serveMetricsAddr)
 	return srv.Start()
 }
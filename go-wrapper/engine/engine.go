@@ -1,40 +1,74 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/cloudchase/inference-runtime/backend"
 	"github.com/cloudchase/inference-runtime/bindings"
+	"github.com/cloudchase/inference-runtime/proto"
 )
 
-// Engine wraps the low-level bindings.Context and provides a
-// higher-level interface for model loading and text generation.
+// Engine dispatches model loading and generation to a backend process
+// (ir-backend-llama by default) over backend.Client, rather than calling
+// bindings.Context in-process. Apart from LoadModel taking the backend name
+// to use (see backend.NameFor), callers don't need to know which backend
+// process (if any) is currently serving a given model.
 type Engine struct {
-	ctx       *bindings.Context
-	modelPath string
-	loaded    bool
+	registry       *backend.Registry
+	computeBackend bindings.BackendType
+
+	client      *backend.Client
+	backendName string
+	modelPath   string
+	loaded      bool
 }
 
-// New creates a new Engine with a CPU backend context.
+// New creates a new Engine targeting a CPU compute backend.
 func New() (*Engine, error) {
-	ctx, err := bindings.NewContext(bindings.BackendCPU)
-	if err != nil {
-		return nil, fmt.Errorf("engine init: %w", err)
-	}
-	return &Engine{ctx: ctx}, nil
+	return NewWithBackend(bindings.BackendCPU)
 }
 
-// NewWithBackend creates a new Engine with the specified backend.
-func NewWithBackend(backend bindings.BackendType) (*Engine, error) {
-	ctx, err := bindings.NewContext(backend)
-	if err != nil {
-		return nil, fmt.Errorf("engine init: %w", err)
-	}
-	return &Engine{ctx: ctx}, nil
+// NewWithBackend creates a new Engine targeting the specified compute
+// backend. It doesn't spawn a backend process yet; that happens on the
+// first LoadModel call, once the required backend name is known.
+func NewWithBackend(computeBackend bindings.BackendType) (*Engine, error) {
+	return &Engine{
+		registry:       backend.DefaultRegistry(),
+		computeBackend: computeBackend,
+	}, nil
 }
 
-// LoadModel loads a GGUF model file into the engine.
-func (e *Engine) LoadModel(path string) error {
-	if err := e.ctx.LoadModel(path); err != nil {
+// LoadModel loads the GGUF (or other) model file at path. backendName
+// selects which backend process serves it (see backend.NameFor); an empty
+// backendName defaults to "llama". If the engine's current backend process
+// already serves backendName, it's reused and the model is swapped in
+// place; otherwise the old process is torn down and a new one spawned.
+func (e *Engine) LoadModel(path, backendName string) error {
+	if backendName == "" {
+		backendName = "llama"
+	}
+
+	if e.client == nil || e.backendName != backendName {
+		if e.client != nil {
+			e.client.Close()
+		}
+		client, err := backend.Spawn(e.registry, backendName)
+		if err != nil {
+			return fmt.Errorf("start backend %q: %w", backendName, err)
+		}
+		e.client = client
+		e.backendName = backendName
+	}
+
+	computeBackend := proto.ComputeCPU
+	if e.computeBackend == bindings.BackendMetal {
+		computeBackend = proto.ComputeMetal
+	}
+	if _, err := e.client.Load(context.Background(), &proto.LoadRequest{
+		ModelPath:      path,
+		ComputeBackend: computeBackend,
+	}); err != nil {
 		return fmt.Errorf("load model: %w", err)
 	}
 	e.modelPath = path
@@ -43,48 +77,102 @@ func (e *Engine) LoadModel(path string) error {
 }
 
 // Generate runs non-streaming text generation and returns the full output.
-func (e *Engine) Generate(prompt string, opts GenerateOptions) (string, error) {
+// If ctx carries a deadline, it bounds how long generation is allowed to
+// run; if ctx is cancelled before generation completes, Generate returns
+// ctx.Err().
+func (e *Engine) Generate(ctx context.Context, prompt string, opts GenerateOptions) (string, error) {
 	if !e.loaded {
 		return "", fmt.Errorf("no model loaded")
 	}
 
-	params := bindings.GenerateParams{
-		MaxTokens:        uint32(opts.MaxTokens),
-		Temperature:      float32(opts.Temperature),
-		TopK:             uint32(opts.TopK),
-		TopP:             float32(opts.TopP),
-		RepetitionPenalty: float32(opts.RepetitionPenalty),
-		Seed:             opts.Seed,
+	resp, err := e.client.Generate(ctx, toGenerateRequest(ctx, prompt, opts))
+	if err != nil {
+		return "", err
 	}
-
-	return e.ctx.Generate(prompt, params)
+	return resp.Text, nil
 }
 
 // GenerateStream runs streaming text generation, calling callback for each token.
-// Return false from callback to stop generation early.
-func (e *Engine) GenerateStream(prompt string, opts GenerateOptions, callback func(string) bool) error {
+// Return false from callback to stop generation early. Generation also stops,
+// with callback receiving no further tokens, as soon as ctx is done — this is
+// how a client disconnect or a per-request timeout aborts an in-flight run.
+func (e *Engine) GenerateStream(ctx context.Context, prompt string, opts GenerateOptions, callback func(string) bool) error {
 	if !e.loaded {
 		return fmt.Errorf("no model loaded")
 	}
 
-	params := bindings.GenerateParams{
-		MaxTokens:        uint32(opts.MaxTokens),
-		Temperature:      float32(opts.Temperature),
-		TopK:             uint32(opts.TopK),
-		TopP:             float32(opts.TopP),
+	return e.client.GenerateStream(ctx, toGenerateRequest(ctx, prompt, opts), func(chunk *proto.TokenChunk) bool {
+		return callback(chunk.Token)
+	})
+}
+
+func toGenerateRequest(ctx context.Context, prompt string, opts GenerateOptions) *proto.GenerateRequest {
+	req := &proto.GenerateRequest{
+		Prompt:            prompt,
+		MaxTokens:         uint32(opts.MaxTokens),
+		Temperature:       float32(opts.Temperature),
+		TopK:              uint32(opts.TopK),
+		TopP:              float32(opts.TopP),
 		RepetitionPenalty: float32(opts.RepetitionPenalty),
-		Seed:             opts.Seed,
+		Seed:              opts.Seed,
+		Stop:              opts.Stop,
 	}
+	if dl, ok := ctx.Deadline(); ok {
+		req.DeadlineUnixMs = uint64(dl.UnixMilli())
+	}
+	return req
+}
 
-	return e.ctx.GenerateStreaming(prompt, params, callback)
+// CountTokens returns how many tokens the loaded model's tokenizer produces
+// for text, for reporting accurate prompt/completion usage counts without
+// running generation.
+func (e *Engine) CountTokens(ctx context.Context, text string) (int, error) {
+	if !e.loaded {
+		return 0, fmt.Errorf("no model loaded")
+	}
+	resp, err := e.client.Tokenize(ctx, &proto.TokenizeRequest{Text: text})
+	if err != nil {
+		return 0, err
+	}
+	return len(resp.Tokens), nil
+}
+
+// Embed returns the loaded model's embedding vector for text. It requires
+// an embedding-capable backend; it returns whatever error the backend gives
+// when the currently loaded model doesn't support it.
+func (e *Engine) Embed(ctx context.Context, text string) ([]float32, error) {
+	if !e.loaded {
+		return nil, fmt.Errorf("no model loaded")
+	}
+	resp, err := e.client.Embed(ctx, &proto.EmbedRequest{Text: text})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Vector, nil
+}
+
+// KVCacheBytes returns the approximate size in bytes of the loaded model's
+// current KV cache, for reporting via the ir_kv_cache_bytes gauge, as given
+// by the backend's Health response. It returns an error if no model is
+// loaded or the Health call fails; a backend that doesn't report the size
+// yields 0 with no error.
+func (e *Engine) KVCacheBytes(ctx context.Context) (uint64, error) {
+	if !e.loaded {
+		return 0, fmt.Errorf("no model loaded")
+	}
+	resp, err := e.client.Health(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return resp.KVCacheBytes, nil
 }
 
 // Reset clears the engine's KV cache and internal state.
 func (e *Engine) Reset() error {
-	if e.ctx == nil {
+	if e.client == nil {
 		return nil
 	}
-	return e.ctx.Reset()
+	return e.client.Reset(context.Background())
 }
 
 // IsLoaded returns whether a model is currently loaded.
@@ -93,11 +181,11 @@ func (e *Engine) IsLoaded() bool { return e.loaded }
 // ModelPath returns the path of the currently loaded model.
 func (e *Engine) ModelPath() string { return e.modelPath }
 
-// Close destroys the underlying context and frees all resources.
+// Close terminates the engine's backend process, if one was started.
 func (e *Engine) Close() {
-	if e.ctx != nil {
-		e.ctx.Close()
-		e.ctx = nil
+	if e.client != nil {
+		e.client.Close()
+		e.client = nil
 	}
 	e.loaded = false
 }
@@ -0,0 +1,88 @@
+// Package proto defines the message shapes and service contract backend.Client
+// and backend.Serve use to talk to a backend process. backend.proto documents
+// the same contract in protobuf/gRPC service-definition form, as the intended
+// target shape, but nothing here is generated from it or travels over gRPC:
+// there's no protoc/grpc-go toolchain wired into this build, so the types
+// below are hand-maintained to mirror the .proto source, and the actual wire
+// format is the line-delimited JSON framing in backend/wire.go.
+package proto
+
+import "context"
+
+// ComputeBackend selects the low-level compute backend a model-serving
+// backend process should use, e.g. CPU vs Metal.
+type ComputeBackend int
+
+const (
+	ComputeCPU ComputeBackend = iota
+	ComputeMetal
+)
+
+type LoadRequest struct {
+	ModelPath      string
+	ComputeBackend ComputeBackend
+}
+
+type LoadResponse struct{}
+
+type GenerateRequest struct {
+	Prompt            string
+	MaxTokens         uint32
+	Temperature       float32
+	TopK              uint32
+	TopP              float32
+	RepetitionPenalty float32
+	Seed              uint64
+	Stop              []string
+	DeadlineUnixMs    uint64
+}
+
+type GenerateResponse struct {
+	Text string
+}
+
+// TokenChunk is one frame of a GenerateStream response.
+type TokenChunk struct {
+	Token string
+}
+
+type EmbedRequest struct {
+	Text string
+}
+
+type EmbedResponse struct {
+	Vector []float32
+}
+
+type TokenizeRequest struct {
+	Text string
+}
+
+type TokenizeResponse struct {
+	Tokens []int32
+}
+
+type ResetRequest struct{}
+type ResetResponse struct{}
+
+type HealthRequest struct{}
+
+type HealthResponse struct {
+	Loaded       bool
+	ModelPath    string
+	KVCacheBytes uint64
+}
+
+// Backend is the RPC service contract a backend process implements. It
+// mirrors the Backend service in backend.proto 1:1.
+type Backend interface {
+	Load(ctx context.Context, req *LoadRequest) (*LoadResponse, error)
+	Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error)
+	// GenerateStream calls send once per generated token, stopping (without
+	// error) as soon as send returns false.
+	GenerateStream(ctx context.Context, req *GenerateRequest, send func(*TokenChunk) bool) error
+	Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error)
+	Tokenize(ctx context.Context, req *TokenizeRequest) (*TokenizeResponse, error)
+	Reset(ctx context.Context, req *ResetRequest) (*ResetResponse, error)
+	Health(ctx context.Context, req *HealthRequest) (*HealthResponse, error)
+}
@@ -0,0 +1,66 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModelConfig holds per-model generation defaults that don't belong in
+// ModelManifest because they're user/gallery preferences rather than facts
+// read from the model file itself: sampler parameters and a default system
+// prompt. It's stored as a YAML sidecar next to the model's manifest so
+// it's easy to hand-edit, unlike the JSON manifest which is treated as
+// machine-owned.
+type ModelConfig struct {
+	MaxTokens         int      `yaml:"max_tokens,omitempty"`
+	Temperature       float64  `yaml:"temperature,omitempty"`
+	TopK              int      `yaml:"top_k,omitempty"`
+	TopP              float64  `yaml:"top_p,omitempty"`
+	RepetitionPenalty float64  `yaml:"repetition_penalty,omitempty"`
+	Stop              []string `yaml:"stop,omitempty"`
+	// System is a default system prompt prepended to chat requests for
+	// this model when the caller doesn't supply their own.
+	System string `yaml:"system,omitempty"`
+}
+
+// ConfigPath returns the path of name's config sidecar, alongside its
+// manifest in ManifestsDir.
+func (s *Store) ConfigPath(name string) string {
+	return filepath.Join(s.ManifestsDir(), name+".config.yaml")
+}
+
+// SaveConfig writes name's config sidecar to disk as YAML.
+func (s *Store) SaveConfig(name string, c *ModelConfig) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.ConfigPath(name), data, 0644)
+}
+
+// LoadConfig reads name's config sidecar from disk. It returns
+// os.ErrNotExist (wrapped) if name has no config, which callers should
+// treat as "use engine defaults" rather than a hard failure.
+func (s *Store) LoadConfig(name string) (*ModelConfig, error) {
+	data, err := os.ReadFile(s.ConfigPath(name))
+	if err != nil {
+		return nil, err
+	}
+	var c ModelConfig
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetConfig returns name's saved generation-defaults config, or nil if it
+// has none (e.g. it wasn't installed via the gallery).
+func (m *ModelManager) GetConfig(name string) (*ModelConfig, error) {
+	cfg, err := m.store.LoadConfig(name)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return cfg, err
+}
@@ -0,0 +1,204 @@
+package registry
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cloudchase/inference-runtime/registry/gguf"
+)
+
+// DefaultGalleryURL is the curated model index consulted when a gallery
+// command isn't given --index. It's maintained alongside this repo so
+// gallery entries stay in lockstep with the runtime's supported formats.
+const DefaultGalleryURL = "https://raw.githubusercontent.com/cloudchase/inference-runtime/main/gallery.yaml"
+
+// GalleryEntry describes one curated model in a gallery index.
+type GalleryEntry struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	License     string   `yaml:"license"`
+	URLs        []string `yaml:"urls"`
+	SHA256      string   `yaml:"sha256"`
+	Template    string   `yaml:"template"`
+	System      string   `yaml:"system"`
+	Options     struct {
+		MaxTokens         int      `yaml:"max_tokens"`
+		Temperature       float64  `yaml:"temperature"`
+		TopK              int      `yaml:"top_k"`
+		TopP              float64  `yaml:"top_p"`
+		RepetitionPenalty float64  `yaml:"repetition_penalty"`
+		Stop              []string `yaml:"stop"`
+	} `yaml:"options"`
+}
+
+// GalleryIndex is the top-level shape of a gallery YAML document.
+type GalleryIndex struct {
+	Models []GalleryEntry `yaml:"models"`
+}
+
+// fetchGalleryIndex downloads and parses the gallery index at url.
+func fetchGalleryIndex(url string) (*GalleryIndex, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetch gallery index: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch gallery index: unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read gallery index: %w", err)
+	}
+
+	var idx GalleryIndex
+	if err := yaml.Unmarshal(body, &idx); err != nil {
+		return nil, fmt.Errorf("parse gallery index: %w", err)
+	}
+	return &idx, nil
+}
+
+// findGalleryEntry looks up name in idx, case-insensitively.
+func findGalleryEntry(idx *GalleryIndex, name string) (GalleryEntry, error) {
+	for _, e := range idx.Models {
+		if strings.EqualFold(e.Name, name) {
+			return e, nil
+		}
+	}
+	return GalleryEntry{}, fmt.Errorf("gallery entry %q not found", name)
+}
+
+// GalleryList fetches indexURL (DefaultGalleryURL if empty) and returns its
+// entries in index order.
+func (m *ModelManager) GalleryList(indexURL string) ([]GalleryEntry, error) {
+	if indexURL == "" {
+		indexURL = DefaultGalleryURL
+	}
+	idx, err := fetchGalleryIndex(indexURL)
+	if err != nil {
+		return nil, err
+	}
+	return idx.Models, nil
+}
+
+// GallerySearch fetches indexURL and returns entries whose name or
+// description contains query, case-insensitively.
+func (m *ModelManager) GallerySearch(indexURL, query string) ([]GalleryEntry, error) {
+	entries, err := m.GalleryList(indexURL)
+	if err != nil {
+		return nil, err
+	}
+	if query == "" {
+		return entries, nil
+	}
+	q := strings.ToLower(query)
+	var matches []GalleryEntry
+	for _, e := range entries {
+		if strings.Contains(strings.ToLower(e.Name), q) || strings.Contains(strings.ToLower(e.Description), q) {
+			matches = append(matches, e)
+		}
+	}
+	return matches, nil
+}
+
+// GalleryInstall downloads the gallery entry named name from indexURL
+// (DefaultGalleryURL if empty), verifies it against the entry's published
+// sha256, and registers it under localName. A downloaded file whose sha256
+// doesn't match is removed and the install fails, rather than leaving a
+// blob behind that could silently serve the wrong model.
+//
+// Alongside the ModelManifest, GalleryInstall writes a <localName>.config.yaml
+// carrying the entry's default sampler options and system prompt, so
+// serve/run can pre-fill generation options for this model without the
+// caller repeating --temperature/--top-p/--system every time.
+func (m *ModelManager) GalleryInstall(indexURL, name, localName string, progress ProgressFunc) error {
+	if indexURL == "" {
+		indexURL = DefaultGalleryURL
+	}
+	idx, err := fetchGalleryIndex(indexURL)
+	if err != nil {
+		return err
+	}
+	entry, err := findGalleryEntry(idx, name)
+	if err != nil {
+		return err
+	}
+	if len(entry.URLs) == 0 {
+		return fmt.Errorf("gallery entry %q has no download URLs", name)
+	}
+	if entry.SHA256 == "" {
+		return fmt.Errorf("gallery entry %q has no sha256; refusing to install unverifiable model", name)
+	}
+	if localName == "" {
+		localName = strings.ToLower(entry.Name)
+	}
+
+	blobPath := filepath.Join(m.store.BlobsDir(), localName+".gguf")
+	var lastErr error
+	for _, url := range entry.URLs {
+		if lastErr = downloadWithResume(url, blobPath, 0, progress); lastErr == nil {
+			break
+		}
+	}
+	if lastErr != nil {
+		return fmt.Errorf("download %s: %w", name, lastErr)
+	}
+
+	if err := verifySHA256(blobPath, entry.SHA256); err != nil {
+		_ = os.Remove(blobPath)
+		return err
+	}
+
+	info, err := os.Stat(blobPath)
+	if err != nil {
+		return err
+	}
+
+	manifest := &ModelManifest{
+		Name:         localName,
+		Path:         blobPath,
+		Size:         info.Size(),
+		ChatTemplate: entry.Template,
+		AddedAt:      time.Now(),
+	}
+
+	if gf, err := gguf.Read(blobPath); err == nil {
+		manifest.Architecture = gf.Architecture()
+		manifest.Parameters = gguf.FormatParameterCount(gf.ParameterCount())
+		manifest.Quantization = gf.Quantization()
+		if manifest.ChatTemplate == "" {
+			manifest.ChatTemplate = gf.ChatTemplate()
+		}
+		manifest.BOSToken = gf.BOSToken()
+		manifest.EOSToken = gf.EOSToken()
+		if manifest.EOSToken != "" {
+			manifest.StopSequences = []string{manifest.EOSToken}
+		}
+	}
+	if len(entry.Options.Stop) > 0 {
+		manifest.StopSequences = entry.Options.Stop
+	}
+
+	if err := m.store.SaveManifest(manifest); err != nil {
+		return err
+	}
+
+	cfg := &ModelConfig{
+		MaxTokens:         entry.Options.MaxTokens,
+		Temperature:       entry.Options.Temperature,
+		TopK:              entry.Options.TopK,
+		TopP:              entry.Options.TopP,
+		RepetitionPenalty: entry.Options.RepetitionPenalty,
+		Stop:              entry.Options.Stop,
+		System:            entry.System,
+	}
+	return m.store.SaveConfig(localName, cfg)
+}
@@ -0,0 +1,55 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGalleryInstall_SHA256Mismatch checks that a downloaded blob failing
+// its published sha256 is removed rather than left behind: a later retry
+// against a fixed index must re-download from scratch instead of silently
+// reusing the corrupted bytes (GalleryInstall passes no expected size, so
+// downloadWithResume would resume from a leftover file's size with a Range
+// request and keep its bytes).
+func TestGalleryInstall_SHA256Mismatch(t *testing.T) {
+	const blobBody = "not actually a gguf file"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/model.bin", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, blobBody)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	// The index needs to point back at the server's own (dynamically
+	// assigned) URL, so it's registered after srv starts.
+	mux.HandleFunc("/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `
+models:
+  - name: test-model
+    description: a model
+    sha256: "0000000000000000000000000000000000000000000000000000000000000000"
+    urls:
+      - %s/model.bin
+`, srv.URL)
+	})
+
+	mgr, err := NewModelManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewModelManager: %v", err)
+	}
+
+	err = mgr.GalleryInstall(srv.URL+"/index.yaml", "test-model", "", nil)
+	if err == nil {
+		t.Fatal("GalleryInstall: want error for sha256 mismatch, got nil")
+	}
+
+	blobPath := filepath.Join(mgr.store.BlobsDir(), "test-model.gguf")
+	if _, statErr := os.Stat(blobPath); !os.IsNotExist(statErr) {
+		t.Fatalf("blob at %s should have been removed after sha256 mismatch, stat error: %v", blobPath, statErr)
+	}
+}
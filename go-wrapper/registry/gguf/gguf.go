@@ -0,0 +1,436 @@
+// Package gguf reads the header of a GGUF model file: the magic/version
+// preamble, the metadata key/value block, and the tensor-info block. It
+// never reads tensor data itself, so parsing a multi-gigabyte model file
+// costs only a handful of reads at the front of it.
+package gguf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const magic = "GGUF"
+
+// maxCount bounds any count read from a GGUF header (metadata KV count,
+// tensor count, array length, string length) before it's used as a
+// slice/map allocation capacity. Real GGUF files never come close to this;
+// a truncated or corrupted download can put an arbitrary 64-bit value
+// there, and without this check that value goes straight into
+// make(..., count) and panics.
+const maxCount = 100_000_000
+
+// File is the parsed header of a GGUF model file.
+type File struct {
+	Version uint32
+	// KV holds every metadata key/value pair verbatim, keyed by its GGUF
+	// name (e.g. "general.architecture"). Values are string, bool, one of
+	// Go's fixed-size integer types (uint8/int8 through uint64/int64),
+	// float32 or float64, or a []any for array-typed entries.
+	KV map[string]any
+	// Tensors is the tensor-info block: name, shape, and storage type for
+	// every tensor, in file order.
+	Tensors []TensorInfo
+}
+
+// TensorInfo describes one tensor's shape and storage type, as read from
+// the tensor-info block. Offset is the tensor's byte offset into the file's
+// data section, relative to the (alignment-padded) end of the tensor-info
+// block; Read does not resolve it further since it never reads tensor data.
+type TensorInfo struct {
+	Name   string
+	Dims   []uint64
+	Type   Type
+	Offset uint64
+}
+
+// ElementCount returns the number of scalar elements in the tensor, i.e.
+// the product of its dimensions.
+func (t TensorInfo) ElementCount() uint64 {
+	n := uint64(1)
+	for _, d := range t.Dims {
+		n *= d
+	}
+	return n
+}
+
+// Type is a ggml tensor storage type, as used in the tensor-info block.
+type Type uint32
+
+// Storage types in ggml_type enum order. Only the ones GGUF files commonly
+// carry are named; anything else renders as "TYPE_<n>".
+const (
+	TypeF32 Type = iota
+	TypeF16
+	TypeQ4_0
+	TypeQ4_1
+	typeReserved4
+	typeReserved5
+	TypeQ5_0
+	TypeQ5_1
+	TypeQ8_0
+	TypeQ8_1
+	TypeQ2_K
+	TypeQ3_K
+	TypeQ4_K
+	TypeQ5_K
+	TypeQ6_K
+	TypeQ8_K
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeF32:
+		return "F32"
+	case TypeF16:
+		return "F16"
+	case TypeQ4_0:
+		return "Q4_0"
+	case TypeQ4_1:
+		return "Q4_1"
+	case TypeQ5_0:
+		return "Q5_0"
+	case TypeQ5_1:
+		return "Q5_1"
+	case TypeQ8_0:
+		return "Q8_0"
+	case TypeQ8_1:
+		return "Q8_1"
+	case TypeQ2_K:
+		return "Q2_K"
+	case TypeQ3_K:
+		return "Q3_K"
+	case TypeQ4_K:
+		return "Q4_K"
+	case TypeQ5_K:
+		return "Q5_K"
+	case TypeQ6_K:
+		return "Q6_K"
+	case TypeQ8_K:
+		return "Q8_K"
+	default:
+		return fmt.Sprintf("TYPE_%d", uint32(t))
+	}
+}
+
+// valueType is the GGUF metadata value type tag, distinct from the tensor
+// storage Type above.
+type valueType uint32
+
+const (
+	vtUint8 valueType = iota
+	vtInt8
+	vtUint16
+	vtInt16
+	vtUint32
+	vtInt32
+	vtFloat32
+	vtBool
+	vtString
+	vtArray
+	vtUint64
+	vtInt64
+	vtFloat64
+)
+
+// Read parses path's GGUF header, metadata KV block, and tensor-info block.
+func Read(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+
+	var gotMagic [4]byte
+	if _, err := io.ReadFull(r, gotMagic[:]); err != nil {
+		return nil, fmt.Errorf("read magic: %w", err)
+	}
+	if string(gotMagic[:]) != magic {
+		return nil, fmt.Errorf("not a GGUF file: bad magic %q", gotMagic)
+	}
+
+	var version uint32
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, fmt.Errorf("read version: %w", err)
+	}
+
+	var tensorCount, kvCount uint64
+	if err := binary.Read(r, binary.LittleEndian, &tensorCount); err != nil {
+		return nil, fmt.Errorf("read tensor_count: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &kvCount); err != nil {
+		return nil, fmt.Errorf("read metadata_kv_count: %w", err)
+	}
+	if tensorCount > maxCount {
+		return nil, fmt.Errorf("tensor_count %d exceeds sane bound, file is likely truncated or corrupt", tensorCount)
+	}
+	if kvCount > maxCount {
+		return nil, fmt.Errorf("metadata_kv_count %d exceeds sane bound, file is likely truncated or corrupt", kvCount)
+	}
+
+	kv := make(map[string]any, kvCount)
+	for i := uint64(0); i < kvCount; i++ {
+		key, err := readString(r)
+		if err != nil {
+			return nil, fmt.Errorf("read metadata key %d: %w", i, err)
+		}
+		var vt valueType
+		if err := binary.Read(r, binary.LittleEndian, &vt); err != nil {
+			return nil, fmt.Errorf("read metadata type for %q: %w", key, err)
+		}
+		val, err := readValue(r, vt)
+		if err != nil {
+			return nil, fmt.Errorf("read metadata value for %q: %w", key, err)
+		}
+		kv[key] = val
+	}
+
+	tensors := make([]TensorInfo, 0, tensorCount)
+	for i := uint64(0); i < tensorCount; i++ {
+		name, err := readString(r)
+		if err != nil {
+			return nil, fmt.Errorf("read tensor %d name: %w", i, err)
+		}
+		var nDims uint32
+		if err := binary.Read(r, binary.LittleEndian, &nDims); err != nil {
+			return nil, fmt.Errorf("read tensor %q dim count: %w", name, err)
+		}
+		dims := make([]uint64, nDims)
+		for d := range dims {
+			if err := binary.Read(r, binary.LittleEndian, &dims[d]); err != nil {
+				return nil, fmt.Errorf("read tensor %q dims: %w", name, err)
+			}
+		}
+		var typ Type
+		if err := binary.Read(r, binary.LittleEndian, &typ); err != nil {
+			return nil, fmt.Errorf("read tensor %q type: %w", name, err)
+		}
+		var offset uint64
+		if err := binary.Read(r, binary.LittleEndian, &offset); err != nil {
+			return nil, fmt.Errorf("read tensor %q offset: %w", name, err)
+		}
+		tensors = append(tensors, TensorInfo{Name: name, Dims: dims, Type: typ, Offset: offset})
+	}
+
+	return &File{Version: version, KV: kv, Tensors: tensors}, nil
+}
+
+func readString(r io.Reader) (string, error) {
+	var n uint64
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return "", err
+	}
+	if n > maxCount {
+		return "", fmt.Errorf("string length %d exceeds sane bound, file is likely truncated or corrupt", n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func readValue(r io.Reader, vt valueType) (any, error) {
+	switch vt {
+	case vtUint8:
+		var v uint8
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	case vtInt8:
+		var v int8
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	case vtUint16:
+		var v uint16
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	case vtInt16:
+		var v int16
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	case vtUint32:
+		var v uint32
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	case vtInt32:
+		var v int32
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	case vtFloat32:
+		var v float32
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	case vtBool:
+		var v uint8
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v != 0, err
+	case vtString:
+		return readString(r)
+	case vtUint64:
+		var v uint64
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	case vtInt64:
+		var v int64
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	case vtFloat64:
+		var v float64
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	case vtArray:
+		var elemType valueType
+		if err := binary.Read(r, binary.LittleEndian, &elemType); err != nil {
+			return nil, err
+		}
+		var n uint64
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		if n > maxCount {
+			return nil, fmt.Errorf("array length %d exceeds sane bound, file is likely truncated or corrupt", n)
+		}
+		arr := make([]any, n)
+		for i := range arr {
+			v, err := readValue(r, elemType)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = v
+		}
+		return arr, nil
+	default:
+		return nil, fmt.Errorf("unknown metadata value type %d", vt)
+	}
+}
+
+// Architecture returns the model's general.architecture metadata key, or ""
+// if the file doesn't carry one.
+func (f *File) Architecture() string {
+	s, _ := f.KV["general.architecture"].(string)
+	return s
+}
+
+// Name returns the model's general.name metadata key, or "" if the file
+// doesn't carry one.
+func (f *File) Name() string {
+	s, _ := f.KV["general.name"].(string)
+	return s
+}
+
+// ParameterCount returns the model's total parameter count: directly from
+// general.parameter_count if the file carries that key, otherwise derived
+// by summing every tensor's element count in the tensor-info block.
+func (f *File) ParameterCount() uint64 {
+	if v, ok := f.KV["general.parameter_count"]; ok {
+		if n, ok := toUint64(v); ok {
+			return n
+		}
+	}
+	var total uint64
+	for _, t := range f.Tensors {
+		total += t.ElementCount()
+	}
+	return total
+}
+
+func toUint64(v any) (uint64, bool) {
+	switch n := v.(type) {
+	case uint64:
+		return n, true
+	case int64:
+		return uint64(n), true
+	case uint32:
+		return uint64(n), true
+	case int32:
+		return uint64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// ChatTemplate returns the model's tokenizer.chat_template metadata key —
+// Jinja2 source, in the Hugging Face convention — or "" if the file
+// doesn't carry one.
+func (f *File) ChatTemplate() string {
+	s, _ := f.KV["tokenizer.chat_template"].(string)
+	return s
+}
+
+// BOSToken returns the text of the model's beginning-of-sequence token,
+// resolved from tokenizer.ggml.bos_token_id through the
+// tokenizer.ggml.tokens vocabulary. It returns "" if either key is
+// missing or the id doesn't resolve to an entry in tokens.
+func (f *File) BOSToken() string {
+	return f.vocabToken("tokenizer.ggml.bos_token_id")
+}
+
+// EOSToken is BOSToken's counterpart for tokenizer.ggml.eos_token_id.
+func (f *File) EOSToken() string {
+	return f.vocabToken("tokenizer.ggml.eos_token_id")
+}
+
+func (f *File) vocabToken(idKey string) string {
+	id, ok := toUint64(f.KV[idKey])
+	if !ok {
+		return ""
+	}
+	tokens, _ := f.KV["tokenizer.ggml.tokens"].([]any)
+	if id >= uint64(len(tokens)) {
+		return ""
+	}
+	s, _ := tokens[id].(string)
+	return s
+}
+
+// Quantization returns a human-readable tag (e.g. "Q4_K", "F16") for the
+// storage type used by most of the model's tensors, which is the closest
+// single-value approximation of "the model's quantization" the tensor-info
+// block offers. F32 is excluded from the count when anything else is
+// present, since norms and biases are stored as F32 regardless of how the
+// rest of the model is quantized.
+func (f *File) Quantization() string {
+	counts := make(map[Type]int)
+	for _, t := range f.Tensors {
+		counts[t.Type]++
+	}
+	if len(counts) > 1 {
+		delete(counts, TypeF32)
+	}
+
+	var best Type
+	bestCount := -1
+	for t, c := range counts {
+		if c > bestCount {
+			best, bestCount = t, c
+		}
+	}
+	if bestCount <= 0 {
+		return ""
+	}
+	return best.String()
+}
+
+// FormatParameterCount renders a raw parameter count the way model cards
+// conventionally do: "7B", "13B", "125M".
+func FormatParameterCount(n uint64) string {
+	switch {
+	case n >= 1e9:
+		return trimFloat(float64(n)/1e9) + "B"
+	case n >= 1e6:
+		return trimFloat(float64(n)/1e6) + "M"
+	case n >= 1e3:
+		return trimFloat(float64(n)/1e3) + "K"
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+func trimFloat(f float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", f), ".0")
+}
@@ -0,0 +1,150 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ggufBuilder assembles a minimal well-formed GGUF byte stream for tests,
+// writing only the pieces Read actually parses (magic/version/counts, the
+// metadata KV block, and the tensor-info block).
+type ggufBuilder struct {
+	buf bytes.Buffer
+}
+
+func newGGUFBuilder(tensorCount, kvCount uint64) *ggufBuilder {
+	b := &ggufBuilder{}
+	b.buf.WriteString(magic)
+	binary.Write(&b.buf, binary.LittleEndian, uint32(3))
+	binary.Write(&b.buf, binary.LittleEndian, tensorCount)
+	binary.Write(&b.buf, binary.LittleEndian, kvCount)
+	return b
+}
+
+func (b *ggufBuilder) writeString(s string) {
+	binary.Write(&b.buf, binary.LittleEndian, uint64(len(s)))
+	b.buf.WriteString(s)
+}
+
+func (b *ggufBuilder) kvString(key, val string) {
+	b.writeString(key)
+	binary.Write(&b.buf, binary.LittleEndian, vtString)
+	b.writeString(val)
+}
+
+// kvStringWithClaimedLen writes a string-typed KV entry whose declared
+// value length doesn't match how many bytes actually follow (there are
+// none), simulating a truncated/corrupt file with a bogus length prefix.
+func (b *ggufBuilder) kvStringWithClaimedLen(key string, valueLen uint64) {
+	b.writeString(key)
+	binary.Write(&b.buf, binary.LittleEndian, vtString)
+	binary.Write(&b.buf, binary.LittleEndian, valueLen)
+}
+
+func (b *ggufBuilder) kvUint32(key string, val uint32) {
+	b.writeString(key)
+	binary.Write(&b.buf, binary.LittleEndian, vtUint32)
+	binary.Write(&b.buf, binary.LittleEndian, val)
+}
+
+func (b *ggufBuilder) kvStringArray(key string, vals []string) {
+	b.writeString(key)
+	binary.Write(&b.buf, binary.LittleEndian, vtArray)
+	binary.Write(&b.buf, binary.LittleEndian, vtString)
+	binary.Write(&b.buf, binary.LittleEndian, uint64(len(vals)))
+	for _, v := range vals {
+		b.writeString(v)
+	}
+}
+
+// kvArrayWithClaimedLen writes an array-typed KV entry whose declared
+// length (n) doesn't match how many elements actually follow, simulating a
+// truncated/corrupt file.
+func (b *ggufBuilder) kvArrayWithClaimedLen(key string, elemType valueType, n uint64) {
+	b.writeString(key)
+	binary.Write(&b.buf, binary.LittleEndian, vtArray)
+	binary.Write(&b.buf, binary.LittleEndian, elemType)
+	binary.Write(&b.buf, binary.LittleEndian, n)
+}
+
+func (b *ggufBuilder) writeFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "model.gguf")
+	if err := os.WriteFile(path, b.buf.Bytes(), 0644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	return path
+}
+
+func TestRead_ValidFile(t *testing.T) {
+	b := newGGUFBuilder(0, 4)
+	b.kvString("general.architecture", "llama")
+	b.kvString("tokenizer.chat_template", "{{ message['content'] }}")
+	b.kvUint32("tokenizer.ggml.bos_token_id", 1)
+	b.kvStringArray("tokenizer.ggml.tokens", []string{"<pad>", "<bos>", "<eos>"})
+	path := b.writeFile(t)
+
+	gf, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got := gf.Architecture(); got != "llama" {
+		t.Errorf("Architecture() = %q, want %q", got, "llama")
+	}
+	if got := gf.ChatTemplate(); got != "{{ message['content'] }}" {
+		t.Errorf("ChatTemplate() = %q", got)
+	}
+	if got := gf.BOSToken(); got != "<bos>" {
+		t.Errorf("BOSToken() = %q, want %q", got, "<bos>")
+	}
+}
+
+// TestRead_TruncatedCounts exercises the maxCount bound added after a
+// truncated/corrupt GGUF file (e.g. an interrupted `ir pull`) could put an
+// arbitrary 64-bit count straight into make(..., count) and panic.
+func TestRead_TruncatedCounts(t *testing.T) {
+	tests := []struct {
+		name        string
+		tensorCount uint64
+		kvCount     uint64
+	}{
+		{"kv count exceeds bound", 0, maxCount + 1},
+		{"tensor count exceeds bound", maxCount + 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newGGUFBuilder(tt.tensorCount, tt.kvCount)
+			path := b.writeFile(t)
+
+			_, err := Read(path)
+			if err == nil {
+				t.Fatal("Read: want error for out-of-bounds count, got nil")
+			}
+		})
+	}
+}
+
+func TestRead_TruncatedStringLength(t *testing.T) {
+	b := newGGUFBuilder(0, 1)
+	b.kvStringWithClaimedLen("general.architecture", maxCount+1)
+	path := b.writeFile(t)
+
+	_, err := Read(path)
+	if err == nil {
+		t.Fatal("Read: want error for out-of-bounds string length, got nil")
+	}
+}
+
+func TestRead_TruncatedArrayLength(t *testing.T) {
+	b := newGGUFBuilder(0, 1)
+	b.kvArrayWithClaimedLen("tokenizer.ggml.tokens", vtString, maxCount+1)
+	path := b.writeFile(t)
+
+	_, err := Read(path)
+	if err == nil {
+		t.Fatal("Read: want error for out-of-bounds array length, got nil")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/cloudchase/inference-runtime/registry/gguf"
 )
 
 // ModelManager provides high-level operations for managing local models.
@@ -43,6 +45,19 @@ func (m *ModelManager) AddLocalModel(name, ggufPath string) error {
 		Size:    info.Size(),
 		AddedAt: time.Now(),
 	}
+
+	if gf, err := gguf.Read(abs); err == nil {
+		manifest.Architecture = gf.Architecture()
+		manifest.Parameters = gguf.FormatParameterCount(gf.ParameterCount())
+		manifest.Quantization = gf.Quantization()
+		manifest.ChatTemplate = gf.ChatTemplate()
+		manifest.BOSToken = gf.BOSToken()
+		manifest.EOSToken = gf.EOSToken()
+		if manifest.EOSToken != "" {
+			manifest.StopSequences = []string{manifest.EOSToken}
+		}
+	}
+
 	return m.store.SaveManifest(manifest)
 }
 
@@ -78,3 +93,18 @@ func (m *ModelManager) ResolveModelPath(nameOrPath string) (string, error) {
 	}
 	return manifest.Path, nil
 }
+
+// Inspect returns the raw GGUF metadata key/value map for name's model
+// file, for diagnosing a model's header beyond the narrower set of fields
+// ModelManifest exposes.
+func (m *ModelManager) Inspect(name string) (map[string]any, error) {
+	path, err := m.ResolveModelPath(name)
+	if err != nil {
+		return nil, err
+	}
+	gf, err := gguf.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("read GGUF header: %w", err)
+	}
+	return gf.KV, nil
+}
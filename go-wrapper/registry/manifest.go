@@ -11,4 +11,33 @@ type ModelManifest struct {
 	Parameters   string    `json:"parameters,omitempty"`
 	Quantization string    `json:"quantization,omitempty"`
 	AddedAt      time.Time `json:"added_at"`
+	// Backend names which backend.Registry executable serves this model
+	// (e.g. "llama", "whisper", "stable-diffusion"). Empty means fall back
+	// to Architecture, and ultimately to "llama".
+	Backend string `json:"backend,omitempty"`
+
+	// ChatTemplate is the model's own chat-formatting template, read
+	// verbatim from the GGUF tokenizer.chat_template metadata key at
+	// import time. Real GGUF files carry this as Jinja2 source (the HF
+	// convention), not Go template source; templates.Render is
+	// responsible for translating the common subset before parsing it.
+	// Empty means the model carried no template, and callers should fall
+	// back to a generic rendering.
+	ChatTemplate string `json:"chat_template,omitempty"`
+	// BOSToken and EOSToken are the model's beginning/end-of-sequence
+	// tokens, read from the GGUF tokenizer.ggml.bos_token_id and
+	// tokenizer.ggml.eos_token_id metadata keys.
+	BOSToken string `json:"bos_token,omitempty"`
+	EOSToken string `json:"eos_token,omitempty"`
+	// StopSequences are strings that mark the end of the model's turn. It
+	// defaults to [EOSToken] when the model has one.
+	StopSequences []string `json:"stop_sequences,omitempty"`
+
+	// SourceRepo, SourceRevision, and SourceFile record where a pulled
+	// model came from (e.g. "TheBloke/Llama-2-7B-Chat-GGUF", "main",
+	// "llama-2-7b-chat.Q4_K_M.gguf"), so a pull can be reproduced or
+	// re-verified later. Empty for models added from a local file.
+	SourceRepo     string `json:"source_repo,omitempty"`
+	SourceRevision string `json:"source_revision,omitempty"`
+	SourceFile     string `json:"source_file,omitempty"`
 }
@@ -0,0 +1,296 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloudchase/inference-runtime/registry/gguf"
+)
+
+// hfBaseURL is the HuggingFace Hub origin Pull talks to. It's a var rather
+// than a const so tests can point it at an httptest server.
+var hfBaseURL = "https://huggingface.co"
+
+// ProgressFunc reports download progress as bytes accrue. total is <= 0 if
+// the server didn't report a size for the file.
+type ProgressFunc func(downloaded, total int64)
+
+// PullOptions controls how Pull resolves and downloads a model reference.
+type PullOptions struct {
+	// Quant selects among multiple GGUF files in a repo by quantization tag
+	// (e.g. "Q4_K_M"). Ignored if ref already names an exact file.
+	Quant string
+	// Progress, if set, is called as the download proceeds.
+	Progress ProgressFunc
+}
+
+// modelRef is a parsed HuggingFace model reference of the form
+// "owner/repo[:file.gguf][@revision]".
+type modelRef struct {
+	Repo     string
+	File     string
+	Revision string
+}
+
+func parseModelRef(ref string) modelRef {
+	r := modelRef{Repo: ref, Revision: "main"}
+	if at := strings.LastIndex(r.Repo, "@"); at != -1 {
+		r.Revision = r.Repo[at+1:]
+		r.Repo = r.Repo[:at]
+	}
+	if colon := strings.Index(r.Repo, ":"); colon != -1 {
+		r.File = r.Repo[colon+1:]
+		r.Repo = r.Repo[:colon]
+	}
+	return r
+}
+
+// hfTreeEntry is one entry returned by the HF Hub's repo-tree API.
+type hfTreeEntry struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	LFS  *struct {
+		OID  string `json:"oid"` // hex sha256 for LFS-tracked files
+		Size int64  `json:"size"`
+	} `json:"lfs"`
+}
+
+// listRepoFiles queries the HF Hub for the files in repo@revision.
+func listRepoFiles(repo, revision string) ([]hfTreeEntry, error) {
+	url := fmt.Sprintf("%s/api/models/%s/tree/%s", hfBaseURL, repo, revision)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", repo, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list %s: unexpected status %s", repo, resp.Status)
+	}
+
+	var entries []hfTreeEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decode repo tree for %s: %w", repo, err)
+	}
+	return entries, nil
+}
+
+var quantTagPattern = regexp.MustCompile(`(?i)Q\d(?:_[0-9A-Z]+)*|F16|F32|BF16`)
+
+// quantTag extracts a quantization tag like "Q4_K_M" from a GGUF filename,
+// or "" if none is found.
+func quantTag(filename string) string {
+	return quantTagPattern.FindString(filename)
+}
+
+// selectFile picks the GGUF file to download from entries, using ref.File
+// (exact match) or opts.Quant (tag match) to disambiguate a repo that
+// publishes more than one quantization.
+func selectFile(entries []hfTreeEntry, ref modelRef, opts PullOptions) (hfTreeEntry, error) {
+	var ggufs []hfTreeEntry
+	for _, e := range entries {
+		if e.Type == "file" && strings.HasSuffix(strings.ToLower(e.Path), ".gguf") {
+			ggufs = append(ggufs, e)
+		}
+	}
+	if len(ggufs) == 0 {
+		return hfTreeEntry{}, fmt.Errorf("no .gguf files found in %s", ref.Repo)
+	}
+
+	if ref.File != "" {
+		for _, e := range ggufs {
+			if e.Path == ref.File {
+				return e, nil
+			}
+		}
+		return hfTreeEntry{}, fmt.Errorf("file %q not found in %s", ref.File, ref.Repo)
+	}
+
+	if opts.Quant != "" {
+		for _, e := range ggufs {
+			if strings.EqualFold(quantTag(e.Path), opts.Quant) {
+				return e, nil
+			}
+		}
+		return hfTreeEntry{}, fmt.Errorf("no .gguf file matching quant %q in %s", opts.Quant, ref.Repo)
+	}
+
+	if len(ggufs) == 1 {
+		return ggufs[0], nil
+	}
+
+	available := make([]string, len(ggufs))
+	for i, e := range ggufs {
+		available[i] = quantTag(e.Path)
+	}
+	return hfTreeEntry{}, fmt.Errorf("%s has multiple .gguf files; pick one with --quant (available: %s)", ref.Repo, strings.Join(available, ", "))
+}
+
+func downloadURL(repo, revision, file string) string {
+	return fmt.Sprintf("%s/%s/resolve/%s/%s", hfBaseURL, repo, revision, file)
+}
+
+// Pull downloads a GGUF model from the HuggingFace Hub and registers it
+// under name. ref is a "owner/repo[:file.gguf][@revision]" reference; when
+// it doesn't name an exact file, opts.Quant (or, failing that, there being
+// only one .gguf in the repo) picks which one to fetch. The download
+// resumes a partial blob via a Range request and is verified against the
+// repo's published sha256 when HF reports the file as a Git LFS object.
+func (m *ModelManager) Pull(name, ref string, opts PullOptions) error {
+	parsed := parseModelRef(ref)
+
+	entries, err := listRepoFiles(parsed.Repo, parsed.Revision)
+	if err != nil {
+		return err
+	}
+	file, err := selectFile(entries, parsed, opts)
+	if err != nil {
+		return err
+	}
+
+	blobPath := filepath.Join(m.store.BlobsDir(), name+".gguf")
+	url := downloadURL(parsed.Repo, parsed.Revision, file.Path)
+	if err := downloadWithResume(url, blobPath, file.Size, opts.Progress); err != nil {
+		return fmt.Errorf("download %s: %w", file.Path, err)
+	}
+
+	if file.LFS != nil && file.LFS.OID != "" {
+		if err := verifySHA256(blobPath, file.LFS.OID); err != nil {
+			_ = os.Remove(blobPath)
+			return err
+		}
+	}
+
+	info, err := os.Stat(blobPath)
+	if err != nil {
+		return err
+	}
+
+	manifest := &ModelManifest{
+		Name:           name,
+		Path:           blobPath,
+		Size:           info.Size(),
+		Quantization:   quantTag(file.Path),
+		SourceRepo:     parsed.Repo,
+		SourceRevision: parsed.Revision,
+		SourceFile:     file.Path,
+		AddedAt:        time.Now(),
+	}
+
+	if gf, err := gguf.Read(blobPath); err == nil {
+		manifest.Architecture = gf.Architecture()
+		manifest.Parameters = gguf.FormatParameterCount(gf.ParameterCount())
+		if q := gf.Quantization(); q != "" {
+			manifest.Quantization = q
+		}
+		manifest.ChatTemplate = gf.ChatTemplate()
+		manifest.BOSToken = gf.BOSToken()
+		manifest.EOSToken = gf.EOSToken()
+		if manifest.EOSToken != "" {
+			manifest.StopSequences = []string{manifest.EOSToken}
+		}
+	}
+
+	return m.store.SaveManifest(manifest)
+}
+
+// downloadWithResume downloads url into dest, resuming from dest's existing
+// size (if any) via a Range request, and reports progress as bytes arrive.
+func downloadWithResume(url, dest string, total int64, progress ProgressFunc) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	var downloaded int64
+	if info, err := os.Stat(dest); err == nil {
+		downloaded = info.Size()
+	}
+	if total > 0 && downloaded >= total {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if downloaded > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", downloaded))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		downloaded = 0
+		flags |= os.O_TRUNC
+	default:
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	f, err := os.OpenFile(dest, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if total <= 0 {
+		total = downloaded + resp.ContentLength
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := f.Write(buf[:n]); err != nil {
+				return err
+			}
+			downloaded += int64(n)
+			if progress != nil {
+				progress(downloaded, total)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	return nil
+}
+
+// verifySHA256 checks path's contents against the expected hex-encoded
+// sha256 digest from the repo's LFS pointer.
+func verifySHA256(path, expected string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	got := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(got, expected) {
+		return fmt.Errorf("sha256 mismatch for %s: got %s, want %s", path, got, expected)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifySHA256_Mismatch(t *testing.T) {
+	const body = "some model bytes"
+	path := filepath.Join(t.TempDir(), "blob.gguf")
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	if err := verifySHA256(path, "0000000000000000000000000000000000000000000000000000000000000000"); err == nil {
+		t.Fatal("verifySHA256: want error for mismatched digest, got nil")
+	}
+
+	// The real digest must still verify, so the failure above is a genuine
+	// mismatch and not a bug in verifySHA256 itself.
+	sum := sha256.Sum256([]byte(body))
+	if err := verifySHA256(path, hex.EncodeToString(sum[:])); err != nil {
+		t.Fatalf("verifySHA256(correct digest): %v", err)
+	}
+}
+
+// TestPull_SHA256Mismatch checks that Pull itself, not just verifySHA256 in
+// isolation, removes a downloaded blob that fails its published sha256: a
+// later retry must re-download from scratch instead of silently reusing the
+// corrupted bytes (downloadWithResume treats an already-the-right-size file
+// on disk as already downloaded).
+func TestPull_SHA256Mismatch(t *testing.T) {
+	const blobBody = "not actually a gguf file"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/models/acme/test-repo/tree/main", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"type": "file", "path": "model.gguf", "size": 24, "lfs": {"oid": "0000000000000000000000000000000000000000000000000000000000000000", "size": 24}}]`)
+	})
+	mux.HandleFunc("/acme/test-repo/resolve/main/model.gguf", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, blobBody)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	restore := hfBaseURL
+	hfBaseURL = srv.URL
+	defer func() { hfBaseURL = restore }()
+
+	mgr, err := NewModelManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewModelManager: %v", err)
+	}
+
+	err = mgr.Pull("test-model", "acme/test-repo", PullOptions{})
+	if err == nil {
+		t.Fatal("Pull: want error for sha256 mismatch, got nil")
+	}
+
+	blobPath := filepath.Join(mgr.store.BlobsDir(), "test-model.gguf")
+	if _, statErr := os.Stat(blobPath); !os.IsNotExist(statErr) {
+		t.Fatalf("blob at %s should have been removed after sha256 mismatch, stat error: %v", blobPath, statErr)
+	}
+}
@@ -0,0 +1,278 @@
+// Package templates renders chat messages into a model's own prompt format,
+// using the chat template read from a model's GGUF metadata
+// (registry.ModelManifest.ChatTemplate) instead of a single hard-coded
+// prefix convention.
+//
+// GGUF's tokenizer.chat_template key holds a Jinja2 template (the HF
+// convention every Llama-3/Qwen/Mistral/Phi/Gemma export uses), not Go
+// template source, so Render runs it through jinjaToGoTemplate first. That
+// translation covers the near-universal shape these templates are built
+// from — a for loop over messages, if/elif/else on message['role'], and
+// 'literal' + message['content'] + 'literal' string concatenation, plus
+// bos_token/eos_token. Anything else — add_generation_prompt, set,
+// loop.last/loop.index0, |trim and other filters, macros — isn't, so stock
+// templates that use those (the usual ChatML and Llama-3 exports among
+// them) are not translated. Render treats that the same as "no usable
+// template": it falls back to an architecture-matched or generic built-in
+// rather than erroring the request.
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/cloudchase/inference-runtime/registry"
+)
+
+// Message is a single chat turn to render.
+type Message struct {
+	Role    string
+	Content string
+}
+
+// Tool describes a function the model may call. Templates that don't
+// support tool calling simply ignore Tools.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+}
+
+// fallbackTemplate renders messages with the fixed English role prefixes
+// the runtime used before per-model templates existed. It's used for
+// models whose manifest carries no ChatTemplate and whose architecture
+// doesn't match a built-in template in architectureTemplates.
+const fallbackTemplate = `{{range .Messages}}{{if eq .Role "system"}}System: {{else if eq .Role "user"}}User: {{else if eq .Role "assistant"}}Assistant: {{else}}{{.Role}}: {{end}}{{.Content}}
+{{end}}Assistant: `
+
+// llama2Template approximates Llama 2's chat format: a single <<SYS>> block
+// carrying all system messages, followed by [INST]/[/INST]-wrapped turns.
+const llama2Template = `[INST] <<SYS>>
+{{range .Messages}}{{if eq .Role "system"}}{{.Content}}
+{{end}}{{end}}<</SYS>>
+
+{{range .Messages}}{{if eq .Role "user"}}{{.Content}} [/INST]{{else if eq .Role "assistant"}} {{.Content}} </s><s>[INST] {{end}}{{end}}`
+
+// mistralTemplate approximates Mistral's instruct format: system messages
+// fold into the instruction block since the base template has no separate
+// system turn.
+const mistralTemplate = `{{range .Messages}}{{if eq .Role "system"}}[INST] {{.Content}}
+{{else if eq .Role "user"}}[INST] {{.Content}} [/INST]{{else if eq .Role "assistant"}}{{.Content}}</s>{{end}}{{end}}`
+
+// chatMLTemplate is the ChatML format used by Qwen and several other
+// architectures: each turn is wrapped in <|im_start|>role/<|im_end|>.
+const chatMLTemplate = `{{range .Messages}}<|im_start|>{{.Role}}
+{{.Content}}<|im_end|>
+{{end}}<|im_start|>assistant
+`
+
+// architectureTemplates are built-in chat templates for well-known model
+// families, consulted when a model's GGUF metadata carries no
+// tokenizer.chat_template of its own. Keyed by the lowercased
+// registry.ModelManifest.Architecture (the GGUF general.architecture value).
+var architectureTemplates = map[string]string{
+	"llama":   llama2Template,
+	"llama2":  llama2Template,
+	"mistral": mistralTemplate,
+	"qwen2":   chatMLTemplate,
+	"chatml":  chatMLTemplate,
+}
+
+var (
+	// jinjaExprBlock matches a Jinja print expression, e.g. {{ 'a' + message['role'] }}.
+	jinjaExprBlock = regexp.MustCompile(`\{\{-?\s*(.*?)\s*-?\}\}`)
+	// jinjaStmtBlock matches a Jinja control-flow statement, e.g. {%- endfor %}.
+	jinjaStmtBlock = regexp.MustCompile(`\{%-?\s*(.*?)\s*-?%\}`)
+	jinjaEquals    = regexp.MustCompile(`(\S+)\s*==\s*(\S+)`)
+)
+
+// jinjaVar maps the handful of names real GGUF chat templates reference —
+// the loop variable's role/content and the tokenizer's bos/eos tokens — to
+// the Go template expression carrying the same value. A quoted string
+// literal is returned unchanged (the caller unquotes it). ok is false for
+// anything else, signalling the caller to give up translating.
+func jinjaVar(tok string) (string, bool) {
+	switch tok {
+	case "message['role']", "message.role":
+		return "$message.Role", true
+	case "message['content']", "message.content":
+		return "$message.Content", true
+	case "bos_token":
+		return ".BOSToken", true
+	case "eos_token":
+		return ".EOSToken", true
+	}
+	if isQuoted(tok) {
+		return tok, true
+	}
+	return "", false
+}
+
+func isQuoted(tok string) bool {
+	return len(tok) >= 2 &&
+		((strings.HasPrefix(tok, "'") && strings.HasSuffix(tok, "'")) ||
+			(strings.HasPrefix(tok, `"`) && strings.HasSuffix(tok, `"`)))
+}
+
+// translateExpr translates the inside of a Jinja {{ ... }} block into
+// literal text interspersed with Go template actions. Real chat templates
+// build their output by concatenating string literals and variables with
+// +, e.g. 'a' + message['role'] + 'b'; translateExpr splits on + and emits
+// each literal operand as output text and each variable operand as a Go
+// action. ok is false if any operand isn't a literal or a name jinjaVar
+// knows.
+func translateExpr(expr string) (out string, ok bool) {
+	var sb strings.Builder
+	for _, p := range strings.Split(expr, "+") {
+		tok := strings.TrimSpace(p)
+		if isQuoted(tok) {
+			unquoted, err := strconv.Unquote(`"` + tok[1:len(tok)-1] + `"`)
+			if err != nil {
+				return "", false
+			}
+			sb.WriteString(unquoted)
+			continue
+		}
+		v, ok := jinjaVar(tok)
+		if !ok {
+			return "", false
+		}
+		sb.WriteString("{{" + v + "}}")
+	}
+	return sb.String(), true
+}
+
+// translateCond translates a Jinja if/elif condition — either a bare
+// variable (truthy check) or an equality comparison against it — into a Go
+// template boolean expression.
+func translateCond(cond string) (string, bool) {
+	if m := jinjaEquals.FindStringSubmatch(cond); m != nil {
+		left, okL := jinjaVar(strings.TrimSpace(m[1]))
+		right, okR := jinjaVar(strings.TrimSpace(m[2]))
+		if !okL || !okR {
+			return "", false
+		}
+		if isQuoted(right) {
+			right = `"` + right[1:len(right)-1] + `"`
+		}
+		return fmt.Sprintf("eq %s %s", left, right), true
+	}
+	return jinjaVar(strings.TrimSpace(cond))
+}
+
+// jinjaToGoTemplate best-effort translates src from the Jinja2 chat-template
+// subset GGUF files carry into Go text/template source. See the package
+// doc comment for what is and isn't covered. ok is false as soon as any
+// block falls outside that subset; Render treats that as "no usable
+// template" rather than a hard error.
+func jinjaToGoTemplate(src string) (string, bool) {
+	ok := true
+
+	// Expression blocks are translated first: once a statement block below
+	// turns e.g. {% for ... %} into Go's {{range ...}}, that would
+	// otherwise look like another (untranslatable) Jinja expression block
+	// to this same pass.
+	src = jinjaExprBlock.ReplaceAllStringFunc(src, func(m string) string {
+		inner := jinjaExprBlock.FindStringSubmatch(m)[1]
+		out, exprOK := translateExpr(inner)
+		if !exprOK {
+			ok = false
+			return m
+		}
+		return out
+	})
+
+	src = jinjaStmtBlock.ReplaceAllStringFunc(src, func(m string) string {
+		inner := strings.TrimSpace(jinjaStmtBlock.FindStringSubmatch(m)[1])
+		switch {
+		case inner == "for message in messages":
+			return "{{range $message := .Messages}}"
+		case inner == "endfor", inner == "endif":
+			return "{{end}}"
+		case inner == "else":
+			return "{{else}}"
+		case strings.HasPrefix(inner, "elif "):
+			cond, condOK := translateCond(inner[len("elif "):])
+			if !condOK {
+				ok = false
+				return m
+			}
+			return "{{else if " + cond + "}}"
+		case strings.HasPrefix(inner, "if "):
+			cond, condOK := translateCond(inner[len("if "):])
+			if !condOK {
+				ok = false
+				return m
+			}
+			return "{{if " + cond + "}}"
+		default:
+			ok = false
+			return m
+		}
+	})
+
+	return src, ok
+}
+
+// templateData is the context exposed to a chat template.
+type templateData struct {
+	Messages []Message
+	Tools    []Tool
+	BOSToken string
+	EOSToken string
+}
+
+// Render renders messages (and, for tool-calling-capable templates, tools)
+// into a single prompt string. override, if non-empty, is used in place of
+// manifest's own ChatTemplate. If both are empty, Render picks a built-in
+// template from architectureTemplates by manifest.Architecture; if that
+// also doesn't match, it falls back to a generic role-prefixed rendering.
+func Render(manifest *registry.ModelManifest, messages []Message, tools []Tool, override string) (string, error) {
+	data := templateData{
+		Messages: messages,
+		Tools:    tools,
+		BOSToken: manifest.BOSToken,
+		EOSToken: manifest.EOSToken,
+	}
+
+	// override and manifest.ChatTemplate come from outside this package
+	// (a GGUF file's tokenizer.chat_template, or a caller-supplied
+	// override) and so are Jinja2, not Go template source; the built-ins
+	// below are ours and need no translation. If a Jinja source doesn't
+	// render after translation, that's not fatal: fall through to the
+	// architecture-matched or generic built-in instead of failing the
+	// request.
+	for _, src := range []string{override, manifest.ChatTemplate} {
+		if src == "" {
+			continue
+		}
+		goSrc, ok := jinjaToGoTemplate(src)
+		if !ok {
+			continue
+		}
+		if out, err := renderTemplate(goSrc, data); err == nil {
+			return out, nil
+		}
+	}
+
+	src := architectureTemplates[strings.ToLower(manifest.Architecture)]
+	if src == "" {
+		src = fallbackTemplate
+	}
+	return renderTemplate(src, data)
+}
+
+func renderTemplate(src string, data templateData) (string, error) {
+	tmpl, err := template.New("chat").Parse(src)
+	if err != nil {
+		return "", fmt.Errorf("parse chat template: %w", err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("render chat template: %w", err)
+	}
+	return sb.String(), nil
+}